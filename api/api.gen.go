@@ -0,0 +1,2699 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.0 DO NOT EDIT.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for ErrorFieldsIn.
+const (
+	Body   ErrorFieldsIn = "body"
+	Header ErrorFieldsIn = "header"
+	Path   ErrorFieldsIn = "path"
+	Query  ErrorFieldsIn = "query"
+)
+
+// Defines values for ImportRowResultKind.
+const (
+	ImportRowResultKindFlat  ImportRowResultKind = "flat"
+	ImportRowResultKindHouse ImportRowResultKind = "house"
+)
+
+// Defines values for Status.
+const (
+	StatusApproved   Status = "approved"
+	StatusCreated    Status = "created"
+	StatusDeclined   Status = "declined"
+	StatusOnModerate Status = "on_moderate"
+)
+
+// Defines values for UserType.
+const (
+	Client    UserType = "client"
+	Moderator UserType = "moderator"
+)
+
+// Defines values for ExportFlatsParamsFormat.
+const (
+	ExportFlatsParamsFormatCsv    ExportFlatsParamsFormat = "csv"
+	ExportFlatsParamsFormatNdjson ExportFlatsParamsFormat = "ndjson"
+	ExportFlatsParamsFormatXlsx   ExportFlatsParamsFormat = "xlsx"
+)
+
+// Defines values for ExportHousesParamsFormat.
+const (
+	ExportHousesParamsFormatCsv    ExportHousesParamsFormat = "csv"
+	ExportHousesParamsFormatNdjson ExportHousesParamsFormat = "ndjson"
+	ExportHousesParamsFormatXlsx   ExportHousesParamsFormat = "xlsx"
+)
+
+// Defines values for UpdateFlatJSONBodyStatus.
+const (
+	UpdateFlatJSONBodyStatusApproved   UpdateFlatJSONBodyStatus = "approved"
+	UpdateFlatJSONBodyStatusDeclined   UpdateFlatJSONBodyStatus = "declined"
+	UpdateFlatJSONBodyStatusOnModerate UpdateFlatJSONBodyStatus = "on_moderate"
+)
+
+// Defines values for SearchFlatsParamsSort.
+const (
+	Newest    SearchFlatsParamsSort = "newest"
+	PriceAsc  SearchFlatsParamsSort = "price_asc"
+	PriceDesc SearchFlatsParamsSort = "price_desc"
+)
+
+// Defines values for ListHouseFlatsParamsSort.
+const (
+	ListHouseFlatsParamsSortNumber ListHouseFlatsParamsSort = "number"
+	ListHouseFlatsParamsSortPrice  ListHouseFlatsParamsSort = "price"
+	ListHouseFlatsParamsSortRooms  ListHouseFlatsParamsSort = "rooms"
+)
+
+// Defines values for ListHouseFlatsParamsOrder.
+const (
+	Asc  ListHouseFlatsParamsOrder = "asc"
+	Desc ListHouseFlatsParamsOrder = "desc"
+)
+
+// Defines values for ImportHousesAndFlatsParamsFormat.
+const (
+	Csv    ImportHousesAndFlatsParamsFormat = "csv"
+	Ndjson ImportHousesAndFlatsParamsFormat = "ndjson"
+)
+
+// Address Адрес дома
+type Address = string
+
+// Date Дата + время
+type Date = time.Time
+
+// Developer Застройщик
+type Developer = string
+
+// Email Email пользователя
+type Email = openapi_types.Email
+
+// Error defines model for Error.
+type Error struct {
+	// Code Код ошибки. Предназначен для классификации проблем и более быстрого решения проблем.
+	Code int `json:"code"`
+
+	// Fields Поля запроса, не соответствующие спецификации
+	Fields *[]struct {
+		// Field Имя параметра или путь к полю тела запроса через точку, пустое для тела целиком
+		Field   string         `json:"field"`
+		In      *ErrorFieldsIn `json:"in,omitempty"`
+		Message string         `json:"message"`
+	} `json:"fields,omitempty"`
+
+	// Message Описание ошибки
+	Message string `json:"message"`
+
+	// RequestId Идентификатор запроса. Предназначен для более быстрого поиска проблем.
+	RequestId *string `json:"request_id,omitempty"`
+}
+
+// ErrorFieldsIn defines model for Error.Fields.In.
+type ErrorFieldsIn string
+
+// Flat Квартира
+type Flat struct {
+	// HouseId Идентификатор дома
+	HouseId HouseId `json:"house_id"`
+
+	// Id Идентификатор квартиры
+	Id FlatId `json:"id"`
+
+	// Number Номер квартиры, уникальный в пределах дома
+	Number FlatNumber `json:"number"`
+
+	// Price Цена квартиры в у.е.
+	Price Price `json:"price"`
+
+	// Rooms Количество комнат в квартире
+	Rooms Rooms `json:"rooms"`
+
+	// Status Статус квартиры
+	Status Status `json:"status"`
+}
+
+// FlatId Идентификатор квартиры
+type FlatId = int
+
+// FlatNumber Номер квартиры, уникальный в пределах дома
+type FlatNumber = int
+
+// House Дом
+type House struct {
+	// Address Адрес дома
+	Address Address `json:"address"`
+
+	// CreatedAt Дата + время
+	CreatedAt *Date `json:"created_at,omitempty"`
+
+	// Developer Застройщик
+	Developer *Developer `json:"developer"`
+
+	// Id Идентификатор дома
+	Id HouseId `json:"id"`
+
+	// UpdatedAt Дата + время
+	UpdatedAt *Date `json:"updated_at,omitempty"`
+
+	// Year Год постройки дома
+	Year Year `json:"year"`
+}
+
+// HouseId Идентификатор дома
+type HouseId = int
+
+// ImportReport Результат импорта
+type ImportReport struct {
+	// Aborted Импорт прерван внутренней ошибкой после сохранения части порций. Сохраненные строки содержат id, остальные -- ошибку с кодом 10702
+	Aborted bool `json:"aborted"`
+	DryRun  bool `json:"dry_run"`
+
+	// Failed Количество несохраненных строк
+	Failed int `json:"failed"`
+
+	// Imported Количество сохраненных строк, для dry_run -- сколько было бы сохранено
+	Imported int               `json:"imported"`
+	Rows     []ImportRowResult `json:"rows"`
+
+	// Total Количество строк в файле
+	Total int `json:"total"`
+}
+
+// ImportRowResult Результат импорта одной строки
+type ImportRowResult struct {
+	// Code Код ошибки, как в Error
+	Code *int `json:"code,omitempty"`
+
+	// Error Почему строка не сохранена
+	Error *string `json:"error,omitempty"`
+
+	// Id Идентификатор созданного дома или квартиры, нет для dry_run и ошибок
+	Id   *int                 `json:"id,omitempty"`
+	Kind *ImportRowResultKind `json:"kind,omitempty"`
+
+	// Line Номер строки в файле, начиная с 1
+	Line int `json:"line"`
+}
+
+// ImportRowResultKind defines model for ImportRowResult.Kind.
+type ImportRowResultKind string
+
+// Password Пароль пользователя
+type Password = string
+
+// Permission Право роли в виде ресурс:действие
+type Permission = string
+
+// Price Цена квартиры в у.е.
+type Price = int
+
+// Role Роль пользователя, роли и их права хранятся в базе
+type Role = string
+
+// Rooms Количество комнат в квартире
+type Rooms = int
+
+// Status Статус квартиры
+type Status string
+
+// Token Авторизационный токен
+type Token = string
+
+// TokenResponse defines model for TokenResponse.
+type TokenResponse struct {
+	// Token Авторизационный токен
+	Token Token `json:"token"`
+}
+
+// UserId Идентификатор пользователя
+type UserId = int
+
+// UserRole defines model for UserRole.
+type UserRole struct {
+	// Id Идентификатор пользователя
+	Id          UserId       `json:"id"`
+	Permissions []Permission `json:"permissions"`
+
+	// Role Роль пользователя, роли и их права хранятся в базе
+	Role Role `json:"role"`
+}
+
+// UserType Тип пользователя
+type UserType string
+
+// Year Год постройки дома
+type Year = int
+
+// N400 defines model for 400.
+type N400 = Error
+
+// N401 defines model for 401.
+type N401 = Error
+
+// N403 defines model for 403.
+type N403 = Error
+
+// N404 defines model for 404.
+type N404 = Error
+
+// N409 defines model for 409.
+type N409 = Error
+
+// N5xx defines model for 5xx.
+type N5xx = Error
+
+// IssueDummyTokenParams defines parameters for IssueDummyToken.
+type IssueDummyTokenParams struct {
+	UserType UserType `form:"user_type" json:"user_type"`
+}
+
+// ExportFlatsParams defines parameters for ExportFlats.
+type ExportFlatsParams struct {
+	// Format Формат выгрузки
+	Format ExportFlatsParamsFormat `form:"format" json:"format"`
+
+	// Status Выгрузить только квартиры в этом статусе
+	Status *Status `form:"status,omitempty" json:"status,omitempty"`
+}
+
+// ExportFlatsParamsFormat defines parameters for ExportFlats.
+type ExportFlatsParamsFormat string
+
+// ExportHousesParams defines parameters for ExportHouses.
+type ExportHousesParams struct {
+	// Format Формат выгрузки
+	Format ExportHousesParamsFormat `form:"format" json:"format"`
+}
+
+// ExportHousesParamsFormat defines parameters for ExportHouses.
+type ExportHousesParamsFormat string
+
+// CreateFlatJSONBody defines parameters for CreateFlat.
+type CreateFlatJSONBody struct {
+	// HouseId Идентификатор дома
+	HouseId HouseId `json:"house_id"`
+
+	// Number Номер квартиры, уникальный в пределах дома
+	Number FlatNumber `json:"number"`
+
+	// Price Цена квартиры в у.е.
+	Price Price `json:"price"`
+
+	// Rooms Количество комнат в квартире
+	Rooms Rooms `json:"rooms"`
+}
+
+// UpdateFlatJSONBody defines parameters for UpdateFlat.
+type UpdateFlatJSONBody struct {
+	// Id Идентификатор квартиры
+	Id FlatId `json:"id"`
+
+	// Status Новый статус квартиры
+	Status UpdateFlatJSONBodyStatus `json:"status"`
+}
+
+// UpdateFlatJSONBodyStatus defines parameters for UpdateFlat.
+type UpdateFlatJSONBodyStatus string
+
+// SearchFlatsParams defines parameters for SearchFlats.
+type SearchFlatsParams struct {
+	PriceFrom *int                   `form:"price_from,omitempty" json:"price_from,omitempty"`
+	PriceTo   *int                   `form:"price_to,omitempty" json:"price_to,omitempty"`
+	Rooms     *int                   `form:"rooms,omitempty" json:"rooms,omitempty"`
+	YearFrom  *int                   `form:"year_from,omitempty" json:"year_from,omitempty"`
+	YearTo    *int                   `form:"year_to,omitempty" json:"year_to,omitempty"`
+	Developer *string                `form:"developer,omitempty" json:"developer,omitempty"`
+	Sort      *SearchFlatsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+	Limit     *int                   `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset    *int                   `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// SearchFlatsParamsSort defines parameters for SearchFlats.
+type SearchFlatsParamsSort string
+
+// CreateHouseJSONBody defines parameters for CreateHouse.
+type CreateHouseJSONBody struct {
+	// Address Адрес дома
+	Address Address `json:"address"`
+
+	// Developer Застройщик
+	Developer *Developer `json:"developer"`
+
+	// Year Год постройки дома
+	Year Year `json:"year"`
+}
+
+// SearchHousesParams defines parameters for SearchHouses.
+type SearchHousesParams struct {
+	Q     string `form:"q" json:"q"`
+	Limit *int   `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListHouseFlatsParams defines parameters for ListHouseFlats.
+type ListHouseFlatsParams struct {
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Размер страницы, по умолчанию 100
+	Limit *int                       `form:"limit,omitempty" json:"limit,omitempty"`
+	Sort  *ListHouseFlatsParamsSort  `form:"sort,omitempty" json:"sort,omitempty"`
+	Order *ListHouseFlatsParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Status Учитывается только для модераторов
+	Status    *Status `form:"status,omitempty" json:"status,omitempty"`
+	Rooms     *int    `form:"rooms,omitempty" json:"rooms,omitempty"`
+	PriceFrom *int    `form:"price_from,omitempty" json:"price_from,omitempty"`
+	PriceTo   *int    `form:"price_to,omitempty" json:"price_to,omitempty"`
+}
+
+// ListHouseFlatsParamsSort defines parameters for ListHouseFlats.
+type ListHouseFlatsParamsSort string
+
+// ListHouseFlatsParamsOrder defines parameters for ListHouseFlats.
+type ListHouseFlatsParamsOrder string
+
+// SubscribeToHouseJSONBody defines parameters for SubscribeToHouse.
+type SubscribeToHouseJSONBody struct {
+	// Email Email пользователя
+	Email Email `json:"email"`
+}
+
+// ImportHousesAndFlatsParams defines parameters for ImportHousesAndFlats.
+type ImportHousesAndFlatsParams struct {
+	// Format Формат файла
+	Format ImportHousesAndFlatsParamsFormat `form:"format" json:"format"`
+
+	// DryRun Проверить файл, включая ограничения базы, и откатить все изменения
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// ChunkSize Количество строк в одной транзакции, 0 -- весь файл
+	ChunkSize *int `form:"chunk_size,omitempty" json:"chunk_size,omitempty"`
+}
+
+// ImportHousesAndFlatsParamsFormat defines parameters for ImportHousesAndFlats.
+type ImportHousesAndFlatsParamsFormat string
+
+// LoginUserJSONBody defines parameters for LoginUser.
+type LoginUserJSONBody struct {
+	// Id Идентификатор пользователя
+	Id UserId `json:"id"`
+
+	// Password Пароль пользователя
+	Password Password `json:"password"`
+}
+
+// RegisterUserJSONBody defines parameters for RegisterUser.
+type RegisterUserJSONBody struct {
+	// Email Email пользователя
+	Email Email `json:"email"`
+
+	// Password Пароль пользователя
+	Password Password `json:"password"`
+
+	// UserType Тип пользователя
+	UserType UserType `json:"user_type"`
+}
+
+// SetUserRoleJSONBody defines parameters for SetUserRole.
+type SetUserRoleJSONBody struct {
+	// Role Роль пользователя, роли и их права хранятся в базе
+	Role Role `json:"role"`
+}
+
+// CreateFlatJSONRequestBody defines body for CreateFlat for application/json ContentType.
+type CreateFlatJSONRequestBody CreateFlatJSONBody
+
+// UpdateFlatJSONRequestBody defines body for UpdateFlat for application/json ContentType.
+type UpdateFlatJSONRequestBody UpdateFlatJSONBody
+
+// CreateHouseJSONRequestBody defines body for CreateHouse for application/json ContentType.
+type CreateHouseJSONRequestBody CreateHouseJSONBody
+
+// SubscribeToHouseJSONRequestBody defines body for SubscribeToHouse for application/json ContentType.
+type SubscribeToHouseJSONRequestBody SubscribeToHouseJSONBody
+
+// LoginUserJSONRequestBody defines body for LoginUser for application/json ContentType.
+type LoginUserJSONRequestBody LoginUserJSONBody
+
+// RegisterUserJSONRequestBody defines body for RegisterUser for application/json ContentType.
+type RegisterUserJSONRequestBody RegisterUserJSONBody
+
+// SetUserRoleJSONRequestBody defines body for SetUserRole for application/json ContentType.
+type SetUserRoleJSONRequestBody SetUserRoleJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+
+	// (GET /dummyLogin)
+	IssueDummyToken(w http.ResponseWriter, r *http.Request, params IssueDummyTokenParams)
+
+	// (GET /export/flats)
+	ExportFlats(w http.ResponseWriter, r *http.Request, params ExportFlatsParams)
+
+	// (GET /export/houses)
+	ExportHouses(w http.ResponseWriter, r *http.Request, params ExportHousesParams)
+
+	// (POST /flat/create)
+	CreateFlat(w http.ResponseWriter, r *http.Request)
+
+	// (POST /flat/update)
+	UpdateFlat(w http.ResponseWriter, r *http.Request)
+
+	// (GET /flats)
+	SearchFlats(w http.ResponseWriter, r *http.Request, params SearchFlatsParams)
+
+	// (POST /house/create)
+	CreateHouse(w http.ResponseWriter, r *http.Request)
+
+	// (GET /house/search)
+	SearchHouses(w http.ResponseWriter, r *http.Request, params SearchHousesParams)
+
+	// (GET /house/{id})
+	ListHouseFlats(w http.ResponseWriter, r *http.Request, id HouseId, params ListHouseFlatsParams)
+
+	// (POST /house/{id}/subscribe)
+	SubscribeToHouse(w http.ResponseWriter, r *http.Request, id HouseId)
+
+	// (POST /import)
+	ImportHousesAndFlats(w http.ResponseWriter, r *http.Request, params ImportHousesAndFlatsParams)
+
+	// (POST /login)
+	LoginUser(w http.ResponseWriter, r *http.Request)
+
+	// (POST /register)
+	RegisterUser(w http.ResponseWriter, r *http.Request)
+
+	// (PUT /user/{id}/role)
+	SetUserRole(w http.ResponseWriter, r *http.Request, id UserId)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// (GET /dummyLogin)
+func (_ Unimplemented) IssueDummyToken(w http.ResponseWriter, r *http.Request, params IssueDummyTokenParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /export/flats)
+func (_ Unimplemented) ExportFlats(w http.ResponseWriter, r *http.Request, params ExportFlatsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /export/houses)
+func (_ Unimplemented) ExportHouses(w http.ResponseWriter, r *http.Request, params ExportHousesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /flat/create)
+func (_ Unimplemented) CreateFlat(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /flat/update)
+func (_ Unimplemented) UpdateFlat(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /flats)
+func (_ Unimplemented) SearchFlats(w http.ResponseWriter, r *http.Request, params SearchFlatsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /house/create)
+func (_ Unimplemented) CreateHouse(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /house/search)
+func (_ Unimplemented) SearchHouses(w http.ResponseWriter, r *http.Request, params SearchHousesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /house/{id})
+func (_ Unimplemented) ListHouseFlats(w http.ResponseWriter, r *http.Request, id HouseId, params ListHouseFlatsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /house/{id}/subscribe)
+func (_ Unimplemented) SubscribeToHouse(w http.ResponseWriter, r *http.Request, id HouseId) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /import)
+func (_ Unimplemented) ImportHousesAndFlats(w http.ResponseWriter, r *http.Request, params ImportHousesAndFlatsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /login)
+func (_ Unimplemented) LoginUser(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /register)
+func (_ Unimplemented) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (PUT /user/{id}/role)
+func (_ Unimplemented) SetUserRole(w http.ResponseWriter, r *http.Request, id UserId) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// IssueDummyToken operation middleware
+func (siw *ServerInterfaceWrapper) IssueDummyToken(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params IssueDummyTokenParams
+
+	// ------------- Required query parameter "user_type" -------------
+
+	if paramValue := r.URL.Query().Get("user_type"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_type"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_type", r.URL.Query(), &params.UserType)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_type", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.IssueDummyToken(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportFlats operation middleware
+func (siw *ServerInterfaceWrapper) ExportFlats(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportFlatsParams
+
+	// ------------- Required query parameter "format" -------------
+
+	if paramValue := r.URL.Query().Get("format"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "format"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportFlats(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportHouses operation middleware
+func (siw *ServerInterfaceWrapper) ExportHouses(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportHousesParams
+
+	// ------------- Required query parameter "format" -------------
+
+	if paramValue := r.URL.Query().Get("format"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "format"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportHouses(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateFlat operation middleware
+func (siw *ServerInterfaceWrapper) CreateFlat(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateFlat(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateFlat operation middleware
+func (siw *ServerInterfaceWrapper) UpdateFlat(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateFlat(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SearchFlats operation middleware
+func (siw *ServerInterfaceWrapper) SearchFlats(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchFlatsParams
+
+	// ------------- Optional query parameter "price_from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_from", r.URL.Query(), &params.PriceFrom)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_to", r.URL.Query(), &params.PriceTo)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "rooms" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "rooms", r.URL.Query(), &params.Rooms)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "rooms", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "year_from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "year_from", r.URL.Query(), &params.YearFrom)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "year_from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "year_to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "year_to", r.URL.Query(), &params.YearTo)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "year_to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "developer" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "developer", r.URL.Query(), &params.Developer)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "developer", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SearchFlats(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateHouse operation middleware
+func (siw *ServerInterfaceWrapper) CreateHouse(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateHouse(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SearchHouses operation middleware
+func (siw *ServerInterfaceWrapper) SearchHouses(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchHousesParams
+
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "q"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SearchHouses(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListHouseFlats operation middleware
+func (siw *ServerInterfaceWrapper) ListHouseFlats(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id HouseId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListHouseFlatsParams
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "order", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "rooms" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "rooms", r.URL.Query(), &params.Rooms)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "rooms", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_from", r.URL.Query(), &params.PriceFrom)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "price_to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "price_to", r.URL.Query(), &params.PriceTo)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "price_to", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListHouseFlats(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SubscribeToHouse operation middleware
+func (siw *ServerInterfaceWrapper) SubscribeToHouse(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id HouseId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SubscribeToHouse(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ImportHousesAndFlats operation middleware
+func (siw *ServerInterfaceWrapper) ImportHousesAndFlats(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportHousesAndFlatsParams
+
+	// ------------- Required query parameter "format" -------------
+
+	if paramValue := r.URL.Query().Get("format"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "format"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "chunk_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "chunk_size", r.URL.Query(), &params.ChunkSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "chunk_size", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ImportHousesAndFlats(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// LoginUser operation middleware
+func (siw *ServerInterfaceWrapper) LoginUser(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LoginUser(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RegisterUser operation middleware
+func (siw *ServerInterfaceWrapper) RegisterUser(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RegisterUser(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SetUserRole operation middleware
+func (siw *ServerInterfaceWrapper) SetUserRole(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id UserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetUserRole(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/dummyLogin", wrapper.IssueDummyToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/export/flats", wrapper.ExportFlats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/export/houses", wrapper.ExportHouses)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/flat/create", wrapper.CreateFlat)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/flat/update", wrapper.UpdateFlat)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/flats", wrapper.SearchFlats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/house/create", wrapper.CreateHouse)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/house/search", wrapper.SearchHouses)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/house/{id}", wrapper.ListHouseFlats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/house/{id}/subscribe", wrapper.SubscribeToHouse)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/import", wrapper.ImportHousesAndFlats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/login", wrapper.LoginUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/register", wrapper.RegisterUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/user/{id}/role", wrapper.SetUserRole)
+	})
+
+	return r
+}
+
+type N400JSONResponse Error
+
+type N401JSONResponse Error
+
+type N403JSONResponse Error
+
+type N404JSONResponse Error
+
+type N409JSONResponse Error
+
+type N5xxResponseHeaders struct {
+	RetryAfter int
+}
+type N5xxJSONResponse struct {
+	Body Error
+
+	Headers N5xxResponseHeaders
+}
+
+type IssueDummyTokenRequestObject struct {
+	Params IssueDummyTokenParams
+}
+
+type IssueDummyTokenResponseObject interface {
+	VisitIssueDummyTokenResponse(w http.ResponseWriter) error
+}
+
+type IssueDummyToken200JSONResponse TokenResponse
+
+func (response IssueDummyToken200JSONResponse) VisitIssueDummyTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type IssueDummyToken400JSONResponse struct{ N400JSONResponse }
+
+func (response IssueDummyToken400JSONResponse) VisitIssueDummyTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type IssueDummyToken500JSONResponse struct{ N5xxJSONResponse }
+
+func (response IssueDummyToken500JSONResponse) VisitIssueDummyTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportFlatsRequestObject struct {
+	Params ExportFlatsParams
+}
+
+type ExportFlatsResponseObject interface {
+	VisitExportFlatsResponse(w http.ResponseWriter) error
+}
+
+type ExportFlats200ResponseHeaders struct {
+	ContentDisposition string
+}
+
+type ExportFlats200ApplicationvndOpenxmlformatsOfficedocumentSpreadsheetmlSheetResponse struct {
+	Body          io.Reader
+	Headers       ExportFlats200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportFlats200ApplicationvndOpenxmlformatsOfficedocumentSpreadsheetmlSheetResponse) VisitExportFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportFlats200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	Headers       ExportFlats200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportFlats200ApplicationxNdjsonResponse) VisitExportFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportFlats200TextcsvResponse struct {
+	Body          io.Reader
+	Headers       ExportFlats200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportFlats200TextcsvResponse) VisitExportFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportFlats400JSONResponse struct{ N400JSONResponse }
+
+func (response ExportFlats400JSONResponse) VisitExportFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportFlats401JSONResponse struct{ N401JSONResponse }
+
+func (response ExportFlats401JSONResponse) VisitExportFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportFlats403JSONResponse struct{ N403JSONResponse }
+
+func (response ExportFlats403JSONResponse) VisitExportFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportFlats500JSONResponse struct{ N5xxJSONResponse }
+
+func (response ExportFlats500JSONResponse) VisitExportFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportHousesRequestObject struct {
+	Params ExportHousesParams
+}
+
+type ExportHousesResponseObject interface {
+	VisitExportHousesResponse(w http.ResponseWriter) error
+}
+
+type ExportHouses200ResponseHeaders struct {
+	ContentDisposition string
+}
+
+type ExportHouses200ApplicationvndOpenxmlformatsOfficedocumentSpreadsheetmlSheetResponse struct {
+	Body          io.Reader
+	Headers       ExportHouses200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportHouses200ApplicationvndOpenxmlformatsOfficedocumentSpreadsheetmlSheetResponse) VisitExportHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportHouses200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	Headers       ExportHouses200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportHouses200ApplicationxNdjsonResponse) VisitExportHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportHouses200TextcsvResponse struct {
+	Body          io.Reader
+	Headers       ExportHouses200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportHouses200TextcsvResponse) VisitExportHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportHouses400JSONResponse struct{ N400JSONResponse }
+
+func (response ExportHouses400JSONResponse) VisitExportHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportHouses401JSONResponse struct{ N401JSONResponse }
+
+func (response ExportHouses401JSONResponse) VisitExportHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportHouses403JSONResponse struct{ N403JSONResponse }
+
+func (response ExportHouses403JSONResponse) VisitExportHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportHouses500JSONResponse struct{ N5xxJSONResponse }
+
+func (response ExportHouses500JSONResponse) VisitExportHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateFlatRequestObject struct {
+	Body *CreateFlatJSONRequestBody
+}
+
+type CreateFlatResponseObject interface {
+	VisitCreateFlatResponse(w http.ResponseWriter) error
+}
+
+type CreateFlat200JSONResponse Flat
+
+func (response CreateFlat200JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response CreateFlat400JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response CreateFlat401JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateFlat404JSONResponse struct{ N404JSONResponse }
+
+func (response CreateFlat404JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateFlat409JSONResponse struct{ N409JSONResponse }
+
+func (response CreateFlat409JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response CreateFlat500JSONResponse) VisitCreateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateFlatRequestObject struct {
+	Body *UpdateFlatJSONRequestBody
+}
+
+type UpdateFlatResponseObject interface {
+	VisitUpdateFlatResponse(w http.ResponseWriter) error
+}
+
+type UpdateFlat200JSONResponse Flat
+
+func (response UpdateFlat200JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateFlat400JSONResponse struct{ N400JSONResponse }
+
+func (response UpdateFlat400JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateFlat401JSONResponse struct{ N401JSONResponse }
+
+func (response UpdateFlat401JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateFlat403JSONResponse struct{ N403JSONResponse }
+
+func (response UpdateFlat403JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateFlat404JSONResponse struct{ N404JSONResponse }
+
+func (response UpdateFlat404JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateFlat500JSONResponse struct{ N5xxJSONResponse }
+
+func (response UpdateFlat500JSONResponse) VisitUpdateFlatResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SearchFlatsRequestObject struct {
+	Params SearchFlatsParams
+}
+
+type SearchFlatsResponseObject interface {
+	VisitSearchFlatsResponse(w http.ResponseWriter) error
+}
+
+type SearchFlats200JSONResponse struct {
+	Flats  []Flat `json:"flats"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+
+	// Total Количество квартир, подходящих под фильтры
+	Total int `json:"total"`
+}
+
+func (response SearchFlats200JSONResponse) VisitSearchFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchFlats400JSONResponse struct{ N400JSONResponse }
+
+func (response SearchFlats400JSONResponse) VisitSearchFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchFlats401JSONResponse struct{ N401JSONResponse }
+
+func (response SearchFlats401JSONResponse) VisitSearchFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchFlats500JSONResponse struct{ N5xxJSONResponse }
+
+func (response SearchFlats500JSONResponse) VisitSearchFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateHouseRequestObject struct {
+	Body *CreateHouseJSONRequestBody
+}
+
+type CreateHouseResponseObject interface {
+	VisitCreateHouseResponse(w http.ResponseWriter) error
+}
+
+type CreateHouse200JSONResponse House
+
+func (response CreateHouse200JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateHouse400JSONResponse struct{ N400JSONResponse }
+
+func (response CreateHouse400JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateHouse401JSONResponse struct{ N401JSONResponse }
+
+func (response CreateHouse401JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateHouse403JSONResponse struct{ N403JSONResponse }
+
+func (response CreateHouse403JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateHouse500JSONResponse struct{ N5xxJSONResponse }
+
+func (response CreateHouse500JSONResponse) VisitCreateHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SearchHousesRequestObject struct {
+	Params SearchHousesParams
+}
+
+type SearchHousesResponseObject interface {
+	VisitSearchHousesResponse(w http.ResponseWriter) error
+}
+
+type SearchHouses200JSONResponse struct {
+	Houses []House `json:"houses"`
+}
+
+func (response SearchHouses200JSONResponse) VisitSearchHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchHouses400JSONResponse struct{ N400JSONResponse }
+
+func (response SearchHouses400JSONResponse) VisitSearchHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchHouses401JSONResponse struct{ N401JSONResponse }
+
+func (response SearchHouses401JSONResponse) VisitSearchHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchHouses500JSONResponse struct{ N5xxJSONResponse }
+
+func (response SearchHouses500JSONResponse) VisitSearchHousesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListHouseFlatsRequestObject struct {
+	Id     HouseId `json:"id"`
+	Params ListHouseFlatsParams
+}
+
+type ListHouseFlatsResponseObject interface {
+	VisitListHouseFlatsResponse(w http.ResponseWriter) error
+}
+
+type ListHouseFlats200ResponseHeaders struct {
+	XNextCursor string
+}
+
+type ListHouseFlats200JSONResponse struct {
+	Body struct {
+		Flats []Flat `json:"flats"`
+	}
+	Headers ListHouseFlats200ResponseHeaders
+}
+
+func (response ListHouseFlats200JSONResponse) VisitListHouseFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Next-Cursor", fmt.Sprint(response.Headers.XNextCursor))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListHouseFlats400JSONResponse struct{ N400JSONResponse }
+
+func (response ListHouseFlats400JSONResponse) VisitListHouseFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListHouseFlats401JSONResponse struct{ N401JSONResponse }
+
+func (response ListHouseFlats401JSONResponse) VisitListHouseFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListHouseFlats500JSONResponse struct{ N5xxJSONResponse }
+
+func (response ListHouseFlats500JSONResponse) VisitListHouseFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SubscribeToHouseRequestObject struct {
+	Id   HouseId `json:"id"`
+	Body *SubscribeToHouseJSONRequestBody
+}
+
+type SubscribeToHouseResponseObject interface {
+	VisitSubscribeToHouseResponse(w http.ResponseWriter) error
+}
+
+type SubscribeToHouse200Response struct {
+}
+
+func (response SubscribeToHouse200Response) VisitSubscribeToHouseResponse(w http.ResponseWriter) error {
+	w.WriteHeader(200)
+	return nil
+}
+
+type SubscribeToHouse400JSONResponse struct{ N400JSONResponse }
+
+func (response SubscribeToHouse400JSONResponse) VisitSubscribeToHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SubscribeToHouse401JSONResponse struct{ N401JSONResponse }
+
+func (response SubscribeToHouse401JSONResponse) VisitSubscribeToHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SubscribeToHouse404JSONResponse struct{ N404JSONResponse }
+
+func (response SubscribeToHouse404JSONResponse) VisitSubscribeToHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SubscribeToHouse500JSONResponse struct{ N5xxJSONResponse }
+
+func (response SubscribeToHouse500JSONResponse) VisitSubscribeToHouseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ImportHousesAndFlatsRequestObject struct {
+	Params ImportHousesAndFlatsParams
+	Body   io.Reader
+}
+
+type ImportHousesAndFlatsResponseObject interface {
+	VisitImportHousesAndFlatsResponse(w http.ResponseWriter) error
+}
+
+type ImportHousesAndFlats200JSONResponse ImportReport
+
+func (response ImportHousesAndFlats200JSONResponse) VisitImportHousesAndFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportHousesAndFlats400JSONResponse struct{ N400JSONResponse }
+
+func (response ImportHousesAndFlats400JSONResponse) VisitImportHousesAndFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportHousesAndFlats401JSONResponse struct{ N401JSONResponse }
+
+func (response ImportHousesAndFlats401JSONResponse) VisitImportHousesAndFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportHousesAndFlats403JSONResponse struct{ N403JSONResponse }
+
+func (response ImportHousesAndFlats403JSONResponse) VisitImportHousesAndFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportHousesAndFlats413JSONResponse Error
+
+func (response ImportHousesAndFlats413JSONResponse) VisitImportHousesAndFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(413)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportHousesAndFlats500JSONResponse struct{ N5xxJSONResponse }
+
+func (response ImportHousesAndFlats500JSONResponse) VisitImportHousesAndFlatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type LoginUserRequestObject struct {
+	Body *LoginUserJSONRequestBody
+}
+
+type LoginUserResponseObject interface {
+	VisitLoginUserResponse(w http.ResponseWriter) error
+}
+
+type LoginUser200JSONResponse TokenResponse
+
+func (response LoginUser200JSONResponse) VisitLoginUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type LoginUser400JSONResponse struct{ N400JSONResponse }
+
+func (response LoginUser400JSONResponse) VisitLoginUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type LoginUser401JSONResponse struct{ N401JSONResponse }
+
+func (response LoginUser401JSONResponse) VisitLoginUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type LoginUser404JSONResponse struct{ N404JSONResponse }
+
+func (response LoginUser404JSONResponse) VisitLoginUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type LoginUser500JSONResponse struct{ N5xxJSONResponse }
+
+func (response LoginUser500JSONResponse) VisitLoginUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RegisterUserRequestObject struct {
+	Body *RegisterUserJSONRequestBody
+}
+
+type RegisterUserResponseObject interface {
+	VisitRegisterUserResponse(w http.ResponseWriter) error
+}
+
+type RegisterUser200JSONResponse struct {
+	// UserId Идентификатор пользователя
+	UserId UserId `json:"user_id"`
+}
+
+func (response RegisterUser200JSONResponse) VisitRegisterUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterUser400JSONResponse struct{ N400JSONResponse }
+
+func (response RegisterUser400JSONResponse) VisitRegisterUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterUser409JSONResponse struct{ N409JSONResponse }
+
+func (response RegisterUser409JSONResponse) VisitRegisterUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterUser500JSONResponse struct{ N5xxJSONResponse }
+
+func (response RegisterUser500JSONResponse) VisitRegisterUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SetUserRoleRequestObject struct {
+	Id   UserId `json:"id"`
+	Body *SetUserRoleJSONRequestBody
+}
+
+type SetUserRoleResponseObject interface {
+	VisitSetUserRoleResponse(w http.ResponseWriter) error
+}
+
+type SetUserRole200JSONResponse UserRole
+
+func (response SetUserRole200JSONResponse) VisitSetUserRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetUserRole400JSONResponse struct{ N400JSONResponse }
+
+func (response SetUserRole400JSONResponse) VisitSetUserRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetUserRole401JSONResponse struct{ N401JSONResponse }
+
+func (response SetUserRole401JSONResponse) VisitSetUserRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetUserRole403JSONResponse struct{ N403JSONResponse }
+
+func (response SetUserRole403JSONResponse) VisitSetUserRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetUserRole404JSONResponse struct{ N404JSONResponse }
+
+func (response SetUserRole404JSONResponse) VisitSetUserRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetUserRole500JSONResponse struct{ N5xxJSONResponse }
+
+func (response SetUserRole500JSONResponse) VisitSetUserRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+
+	// (GET /dummyLogin)
+	IssueDummyToken(ctx context.Context, request IssueDummyTokenRequestObject) (IssueDummyTokenResponseObject, error)
+
+	// (GET /export/flats)
+	ExportFlats(ctx context.Context, request ExportFlatsRequestObject) (ExportFlatsResponseObject, error)
+
+	// (GET /export/houses)
+	ExportHouses(ctx context.Context, request ExportHousesRequestObject) (ExportHousesResponseObject, error)
+
+	// (POST /flat/create)
+	CreateFlat(ctx context.Context, request CreateFlatRequestObject) (CreateFlatResponseObject, error)
+
+	// (POST /flat/update)
+	UpdateFlat(ctx context.Context, request UpdateFlatRequestObject) (UpdateFlatResponseObject, error)
+
+	// (GET /flats)
+	SearchFlats(ctx context.Context, request SearchFlatsRequestObject) (SearchFlatsResponseObject, error)
+
+	// (POST /house/create)
+	CreateHouse(ctx context.Context, request CreateHouseRequestObject) (CreateHouseResponseObject, error)
+
+	// (GET /house/search)
+	SearchHouses(ctx context.Context, request SearchHousesRequestObject) (SearchHousesResponseObject, error)
+
+	// (GET /house/{id})
+	ListHouseFlats(ctx context.Context, request ListHouseFlatsRequestObject) (ListHouseFlatsResponseObject, error)
+
+	// (POST /house/{id}/subscribe)
+	SubscribeToHouse(ctx context.Context, request SubscribeToHouseRequestObject) (SubscribeToHouseResponseObject, error)
+
+	// (POST /import)
+	ImportHousesAndFlats(ctx context.Context, request ImportHousesAndFlatsRequestObject) (ImportHousesAndFlatsResponseObject, error)
+
+	// (POST /login)
+	LoginUser(ctx context.Context, request LoginUserRequestObject) (LoginUserResponseObject, error)
+
+	// (POST /register)
+	RegisterUser(ctx context.Context, request RegisterUserRequestObject) (RegisterUserResponseObject, error)
+
+	// (PUT /user/{id}/role)
+	SetUserRole(ctx context.Context, request SetUserRoleRequestObject) (SetUserRoleResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// IssueDummyToken operation middleware
+func (sh *strictHandler) IssueDummyToken(w http.ResponseWriter, r *http.Request, params IssueDummyTokenParams) {
+	var request IssueDummyTokenRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.IssueDummyToken(ctx, request.(IssueDummyTokenRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "IssueDummyToken")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(IssueDummyTokenResponseObject); ok {
+		if err := validResponse.VisitIssueDummyTokenResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ExportFlats operation middleware
+func (sh *strictHandler) ExportFlats(w http.ResponseWriter, r *http.Request, params ExportFlatsParams) {
+	var request ExportFlatsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportFlats(ctx, request.(ExportFlatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportFlats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportFlatsResponseObject); ok {
+		if err := validResponse.VisitExportFlatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ExportHouses operation middleware
+func (sh *strictHandler) ExportHouses(w http.ResponseWriter, r *http.Request, params ExportHousesParams) {
+	var request ExportHousesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportHouses(ctx, request.(ExportHousesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportHouses")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportHousesResponseObject); ok {
+		if err := validResponse.VisitExportHousesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateFlat operation middleware
+func (sh *strictHandler) CreateFlat(w http.ResponseWriter, r *http.Request) {
+	var request CreateFlatRequestObject
+
+	var body CreateFlatJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateFlat(ctx, request.(CreateFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateFlatResponseObject); ok {
+		if err := validResponse.VisitCreateFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateFlat operation middleware
+func (sh *strictHandler) UpdateFlat(w http.ResponseWriter, r *http.Request) {
+	var request UpdateFlatRequestObject
+
+	var body UpdateFlatJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateFlat(ctx, request.(UpdateFlatRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateFlat")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateFlatResponseObject); ok {
+		if err := validResponse.VisitUpdateFlatResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SearchFlats operation middleware
+func (sh *strictHandler) SearchFlats(w http.ResponseWriter, r *http.Request, params SearchFlatsParams) {
+	var request SearchFlatsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SearchFlats(ctx, request.(SearchFlatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SearchFlats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SearchFlatsResponseObject); ok {
+		if err := validResponse.VisitSearchFlatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateHouse operation middleware
+func (sh *strictHandler) CreateHouse(w http.ResponseWriter, r *http.Request) {
+	var request CreateHouseRequestObject
+
+	var body CreateHouseJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateHouse(ctx, request.(CreateHouseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateHouse")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateHouseResponseObject); ok {
+		if err := validResponse.VisitCreateHouseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SearchHouses operation middleware
+func (sh *strictHandler) SearchHouses(w http.ResponseWriter, r *http.Request, params SearchHousesParams) {
+	var request SearchHousesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SearchHouses(ctx, request.(SearchHousesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SearchHouses")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SearchHousesResponseObject); ok {
+		if err := validResponse.VisitSearchHousesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListHouseFlats operation middleware
+func (sh *strictHandler) ListHouseFlats(w http.ResponseWriter, r *http.Request, id HouseId, params ListHouseFlatsParams) {
+	var request ListHouseFlatsRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListHouseFlats(ctx, request.(ListHouseFlatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListHouseFlats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListHouseFlatsResponseObject); ok {
+		if err := validResponse.VisitListHouseFlatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SubscribeToHouse operation middleware
+func (sh *strictHandler) SubscribeToHouse(w http.ResponseWriter, r *http.Request, id HouseId) {
+	var request SubscribeToHouseRequestObject
+
+	request.Id = id
+
+	var body SubscribeToHouseJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SubscribeToHouse(ctx, request.(SubscribeToHouseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SubscribeToHouse")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SubscribeToHouseResponseObject); ok {
+		if err := validResponse.VisitSubscribeToHouseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ImportHousesAndFlats operation middleware
+func (sh *strictHandler) ImportHousesAndFlats(w http.ResponseWriter, r *http.Request, params ImportHousesAndFlatsParams) {
+	var request ImportHousesAndFlatsRequestObject
+
+	request.Params = params
+
+	request.Body = r.Body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ImportHousesAndFlats(ctx, request.(ImportHousesAndFlatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ImportHousesAndFlats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ImportHousesAndFlatsResponseObject); ok {
+		if err := validResponse.VisitImportHousesAndFlatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// LoginUser operation middleware
+func (sh *strictHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
+	var request LoginUserRequestObject
+
+	var body LoginUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.LoginUser(ctx, request.(LoginUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "LoginUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(LoginUserResponseObject); ok {
+		if err := validResponse.VisitLoginUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RegisterUser operation middleware
+func (sh *strictHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	var request RegisterUserRequestObject
+
+	var body RegisterUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RegisterUser(ctx, request.(RegisterUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RegisterUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RegisterUserResponseObject); ok {
+		if err := validResponse.VisitRegisterUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SetUserRole operation middleware
+func (sh *strictHandler) SetUserRole(w http.ResponseWriter, r *http.Request, id UserId) {
+	var request SetUserRoleRequestObject
+
+	request.Id = id
+
+	var body SetUserRoleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SetUserRole(ctx, request.(SetUserRoleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SetUserRole")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SetUserRoleResponseObject); ok {
+		if err := validResponse.VisitSetUserRoleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	_ "embed"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// The models and the strict server in api.gen.go are generated from api.yaml,
+// handlers implement StrictServerInterface.
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.5.0 -config oapi-codegen.yaml api.yaml
+
+// Spec is the OpenAPI description of the /v2 API. Requests are validated against
+// it and the contract tests check the handlers against it.
+//
+//go:embed api.yaml
+var Spec []byte
+
+// SpecV1 is the OpenAPI description of the deprecated /v1 and unversioned API,
+// only the contract tests check the handlers against it.
+//
+//go:embed v1.yaml
+var SpecV1 []byte
+
+func init() {
+	// kin-openapi leaves the email format unchecked unless it is defined
+	openapi3.DefineStringFormatValidator("email", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForEmail))
+}
+
+// Load parses and validates Spec.
+func Load() (*openapi3.T, error) {
+	return load(Spec, "api.yaml")
+}
+
+// LoadV1 parses and validates SpecV1.
+func LoadV1() (*openapi3.T, error) {
+	return load(SpecV1, "v1.yaml")
+}
+
+func load(spec []byte, name string) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", name, err)
+	}
+
+	if err = doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("validate %s: %w", name, err)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,515 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: housing/v1/flat.proto
+
+package housingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FlatStatus int32
+
+const (
+	FlatStatus_FLAT_STATUS_UNSPECIFIED FlatStatus = 0
+	FlatStatus_FLAT_STATUS_CREATED     FlatStatus = 1
+	FlatStatus_FLAT_STATUS_ON_MODERATE FlatStatus = 2
+	FlatStatus_FLAT_STATUS_APPROVED    FlatStatus = 3
+	FlatStatus_FLAT_STATUS_DECLINED    FlatStatus = 4
+)
+
+// Enum value maps for FlatStatus.
+var (
+	FlatStatus_name = map[int32]string{
+		0: "FLAT_STATUS_UNSPECIFIED",
+		1: "FLAT_STATUS_CREATED",
+		2: "FLAT_STATUS_ON_MODERATE",
+		3: "FLAT_STATUS_APPROVED",
+		4: "FLAT_STATUS_DECLINED",
+	}
+	FlatStatus_value = map[string]int32{
+		"FLAT_STATUS_UNSPECIFIED": 0,
+		"FLAT_STATUS_CREATED":     1,
+		"FLAT_STATUS_ON_MODERATE": 2,
+		"FLAT_STATUS_APPROVED":    3,
+		"FLAT_STATUS_DECLINED":    4,
+	}
+)
+
+func (x FlatStatus) Enum() *FlatStatus {
+	p := new(FlatStatus)
+	*p = x
+	return p
+}
+
+func (x FlatStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FlatStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_housing_v1_flat_proto_enumTypes[0].Descriptor()
+}
+
+func (FlatStatus) Type() protoreflect.EnumType {
+	return &file_housing_v1_flat_proto_enumTypes[0]
+}
+
+func (x FlatStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FlatStatus.Descriptor instead.
+func (FlatStatus) EnumDescriptor() ([]byte, []int) {
+	return file_housing_v1_flat_proto_rawDescGZIP(), []int{0}
+}
+
+type Flat struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	HouseId int64                  `protobuf:"varint,2,opt,name=house_id,json=houseId,proto3" json:"house_id,omitempty"`
+	// Number is unique within the house.
+	Number        int32      `protobuf:"varint,3,opt,name=number,proto3" json:"number,omitempty"`
+	Price         int32      `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	Rooms         int32      `protobuf:"varint,5,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	Status        FlatStatus `protobuf:"varint,6,opt,name=status,proto3,enum=housing.v1.FlatStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Flat) Reset() {
+	*x = Flat{}
+	mi := &file_housing_v1_flat_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Flat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flat) ProtoMessage() {}
+
+func (x *Flat) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_flat_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flat.ProtoReflect.Descriptor instead.
+func (*Flat) Descriptor() ([]byte, []int) {
+	return file_housing_v1_flat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Flat) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Flat) GetHouseId() int64 {
+	if x != nil {
+		return x.HouseId
+	}
+	return 0
+}
+
+func (x *Flat) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Flat) GetPrice() int32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Flat) GetRooms() int32 {
+	if x != nil {
+		return x.Rooms
+	}
+	return 0
+}
+
+func (x *Flat) GetStatus() FlatStatus {
+	if x != nil {
+		return x.Status
+	}
+	return FlatStatus_FLAT_STATUS_UNSPECIFIED
+}
+
+type CreateFlatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HouseId       int64                  `protobuf:"varint,1,opt,name=house_id,json=houseId,proto3" json:"house_id,omitempty"`
+	Number        int32                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	Price         int32                  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	Rooms         int32                  `protobuf:"varint,4,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFlatRequest) Reset() {
+	*x = CreateFlatRequest{}
+	mi := &file_housing_v1_flat_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFlatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFlatRequest) ProtoMessage() {}
+
+func (x *CreateFlatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_flat_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFlatRequest.ProtoReflect.Descriptor instead.
+func (*CreateFlatRequest) Descriptor() ([]byte, []int) {
+	return file_housing_v1_flat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateFlatRequest) GetHouseId() int64 {
+	if x != nil {
+		return x.HouseId
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetPrice() int32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetRooms() int32 {
+	if x != nil {
+		return x.Rooms
+	}
+	return 0
+}
+
+// SearchFlatsRequest filters are ignored when zero.
+type SearchFlatsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PriceFrom int32                  `protobuf:"varint,1,opt,name=price_from,json=priceFrom,proto3" json:"price_from,omitempty"`
+	PriceTo   int32                  `protobuf:"varint,2,opt,name=price_to,json=priceTo,proto3" json:"price_to,omitempty"`
+	Rooms     int32                  `protobuf:"varint,3,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	YearFrom  int32                  `protobuf:"varint,4,opt,name=year_from,json=yearFrom,proto3" json:"year_from,omitempty"`
+	YearTo    int32                  `protobuf:"varint,5,opt,name=year_to,json=yearTo,proto3" json:"year_to,omitempty"`
+	Developer string                 `protobuf:"bytes,6,opt,name=developer,proto3" json:"developer,omitempty"`
+	// Sort is one of price_asc, price_desc or newest.
+	Sort          string `protobuf:"bytes,7,opt,name=sort,proto3" json:"sort,omitempty"`
+	Limit         int32  `protobuf:"varint,8,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32  `protobuf:"varint,9,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchFlatsRequest) Reset() {
+	*x = SearchFlatsRequest{}
+	mi := &file_housing_v1_flat_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchFlatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFlatsRequest) ProtoMessage() {}
+
+func (x *SearchFlatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_flat_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFlatsRequest.ProtoReflect.Descriptor instead.
+func (*SearchFlatsRequest) Descriptor() ([]byte, []int) {
+	return file_housing_v1_flat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SearchFlatsRequest) GetPriceFrom() int32 {
+	if x != nil {
+		return x.PriceFrom
+	}
+	return 0
+}
+
+func (x *SearchFlatsRequest) GetPriceTo() int32 {
+	if x != nil {
+		return x.PriceTo
+	}
+	return 0
+}
+
+func (x *SearchFlatsRequest) GetRooms() int32 {
+	if x != nil {
+		return x.Rooms
+	}
+	return 0
+}
+
+func (x *SearchFlatsRequest) GetYearFrom() int32 {
+	if x != nil {
+		return x.YearFrom
+	}
+	return 0
+}
+
+func (x *SearchFlatsRequest) GetYearTo() int32 {
+	if x != nil {
+		return x.YearTo
+	}
+	return 0
+}
+
+func (x *SearchFlatsRequest) GetDeveloper() string {
+	if x != nil {
+		return x.Developer
+	}
+	return ""
+}
+
+func (x *SearchFlatsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *SearchFlatsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchFlatsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type SearchFlatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Flats []*Flat                `protobuf:"bytes,1,rep,name=flats,proto3" json:"flats,omitempty"`
+	// Total is the number of flats matching the filters.
+	Total         int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchFlatsResponse) Reset() {
+	*x = SearchFlatsResponse{}
+	mi := &file_housing_v1_flat_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchFlatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFlatsResponse) ProtoMessage() {}
+
+func (x *SearchFlatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_flat_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFlatsResponse.ProtoReflect.Descriptor instead.
+func (*SearchFlatsResponse) Descriptor() ([]byte, []int) {
+	return file_housing_v1_flat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SearchFlatsResponse) GetFlats() []*Flat {
+	if x != nil {
+		return x.Flats
+	}
+	return nil
+}
+
+func (x *SearchFlatsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchFlatsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchFlatsResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+var File_housing_v1_flat_proto protoreflect.FileDescriptor
+
+const file_housing_v1_flat_proto_rawDesc = "" +
+	"\n" +
+	"\x15housing/v1/flat.proto\x12\n" +
+	"housing.v1\"\xa5\x01\n" +
+	"\x04Flat\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bhouse_id\x18\x02 \x01(\x03R\ahouseId\x12\x16\n" +
+	"\x06number\x18\x03 \x01(\x05R\x06number\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x05R\x05price\x12\x14\n" +
+	"\x05rooms\x18\x05 \x01(\x05R\x05rooms\x12.\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x16.housing.v1.FlatStatusR\x06status\"r\n" +
+	"\x11CreateFlatRequest\x12\x19\n" +
+	"\bhouse_id\x18\x01 \x01(\x03R\ahouseId\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\x05R\x06number\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x05R\x05price\x12\x14\n" +
+	"\x05rooms\x18\x04 \x01(\x05R\x05rooms\"\xfa\x01\n" +
+	"\x12SearchFlatsRequest\x12\x1d\n" +
+	"\n" +
+	"price_from\x18\x01 \x01(\x05R\tpriceFrom\x12\x19\n" +
+	"\bprice_to\x18\x02 \x01(\x05R\apriceTo\x12\x14\n" +
+	"\x05rooms\x18\x03 \x01(\x05R\x05rooms\x12\x1b\n" +
+	"\tyear_from\x18\x04 \x01(\x05R\byearFrom\x12\x17\n" +
+	"\ayear_to\x18\x05 \x01(\x05R\x06yearTo\x12\x1c\n" +
+	"\tdeveloper\x18\x06 \x01(\tR\tdeveloper\x12\x12\n" +
+	"\x04sort\x18\a \x01(\tR\x04sort\x12\x14\n" +
+	"\x05limit\x18\b \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\t \x01(\x05R\x06offset\"\x81\x01\n" +
+	"\x13SearchFlatsResponse\x12&\n" +
+	"\x05flats\x18\x01 \x03(\v2\x10.housing.v1.FlatR\x05flats\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset*\x93\x01\n" +
+	"\n" +
+	"FlatStatus\x12\x1b\n" +
+	"\x17FLAT_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13FLAT_STATUS_CREATED\x10\x01\x12\x1b\n" +
+	"\x17FLAT_STATUS_ON_MODERATE\x10\x02\x12\x18\n" +
+	"\x14FLAT_STATUS_APPROVED\x10\x03\x12\x18\n" +
+	"\x14FLAT_STATUS_DECLINED\x10\x042\x9c\x01\n" +
+	"\vFlatService\x12=\n" +
+	"\n" +
+	"CreateFlat\x12\x1d.housing.v1.CreateFlatRequest\x1a\x10.housing.v1.Flat\x12N\n" +
+	"\vSearchFlats\x12\x1e.housing.v1.SearchFlatsRequest\x1a\x1f.housing.v1.SearchFlatsResponseBKZIgithub.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1;housingv1b\x06proto3"
+
+var (
+	file_housing_v1_flat_proto_rawDescOnce sync.Once
+	file_housing_v1_flat_proto_rawDescData []byte
+)
+
+func file_housing_v1_flat_proto_rawDescGZIP() []byte {
+	file_housing_v1_flat_proto_rawDescOnce.Do(func() {
+		file_housing_v1_flat_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_housing_v1_flat_proto_rawDesc), len(file_housing_v1_flat_proto_rawDesc)))
+	})
+	return file_housing_v1_flat_proto_rawDescData
+}
+
+var file_housing_v1_flat_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_housing_v1_flat_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_housing_v1_flat_proto_goTypes = []any{
+	(FlatStatus)(0),             // 0: housing.v1.FlatStatus
+	(*Flat)(nil),                // 1: housing.v1.Flat
+	(*CreateFlatRequest)(nil),   // 2: housing.v1.CreateFlatRequest
+	(*SearchFlatsRequest)(nil),  // 3: housing.v1.SearchFlatsRequest
+	(*SearchFlatsResponse)(nil), // 4: housing.v1.SearchFlatsResponse
+}
+var file_housing_v1_flat_proto_depIdxs = []int32{
+	0, // 0: housing.v1.Flat.status:type_name -> housing.v1.FlatStatus
+	1, // 1: housing.v1.SearchFlatsResponse.flats:type_name -> housing.v1.Flat
+	2, // 2: housing.v1.FlatService.CreateFlat:input_type -> housing.v1.CreateFlatRequest
+	3, // 3: housing.v1.FlatService.SearchFlats:input_type -> housing.v1.SearchFlatsRequest
+	1, // 4: housing.v1.FlatService.CreateFlat:output_type -> housing.v1.Flat
+	4, // 5: housing.v1.FlatService.SearchFlats:output_type -> housing.v1.SearchFlatsResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_housing_v1_flat_proto_init() }
+func file_housing_v1_flat_proto_init() {
+	if File_housing_v1_flat_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_housing_v1_flat_proto_rawDesc), len(file_housing_v1_flat_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_housing_v1_flat_proto_goTypes,
+		DependencyIndexes: file_housing_v1_flat_proto_depIdxs,
+		EnumInfos:         file_housing_v1_flat_proto_enumTypes,
+		MessageInfos:      file_housing_v1_flat_proto_msgTypes,
+	}.Build()
+	File_housing_v1_flat_proto = out.File
+	file_housing_v1_flat_proto_goTypes = nil
+	file_housing_v1_flat_proto_depIdxs = nil
+}
@@ -0,0 +1,167 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: housing/v1/flat.proto
+
+package housingv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FlatService_CreateFlat_FullMethodName  = "/housing.v1.FlatService/CreateFlat"
+	FlatService_SearchFlats_FullMethodName = "/housing.v1.FlatService/SearchFlats"
+)
+
+// FlatServiceClient is the client API for FlatService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FlatService creates and searches flats. Any authenticated user may call it.
+type FlatServiceClient interface {
+	// CreateFlat adds a flat to a house, the flat starts in FLAT_STATUS_CREATED.
+	CreateFlat(ctx context.Context, in *CreateFlatRequest, opts ...grpc.CallOption) (*Flat, error)
+	// SearchFlats searches flats across houses. Clients only see approved flats.
+	SearchFlats(ctx context.Context, in *SearchFlatsRequest, opts ...grpc.CallOption) (*SearchFlatsResponse, error)
+}
+
+type flatServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlatServiceClient(cc grpc.ClientConnInterface) FlatServiceClient {
+	return &flatServiceClient{cc}
+}
+
+func (c *flatServiceClient) CreateFlat(ctx context.Context, in *CreateFlatRequest, opts ...grpc.CallOption) (*Flat, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Flat)
+	err := c.cc.Invoke(ctx, FlatService_CreateFlat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flatServiceClient) SearchFlats(ctx context.Context, in *SearchFlatsRequest, opts ...grpc.CallOption) (*SearchFlatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchFlatsResponse)
+	err := c.cc.Invoke(ctx, FlatService_SearchFlats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FlatServiceServer is the server API for FlatService service.
+// All implementations must embed UnimplementedFlatServiceServer
+// for forward compatibility.
+//
+// FlatService creates and searches flats. Any authenticated user may call it.
+type FlatServiceServer interface {
+	// CreateFlat adds a flat to a house, the flat starts in FLAT_STATUS_CREATED.
+	CreateFlat(context.Context, *CreateFlatRequest) (*Flat, error)
+	// SearchFlats searches flats across houses. Clients only see approved flats.
+	SearchFlats(context.Context, *SearchFlatsRequest) (*SearchFlatsResponse, error)
+	mustEmbedUnimplementedFlatServiceServer()
+}
+
+// UnimplementedFlatServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFlatServiceServer struct{}
+
+func (UnimplementedFlatServiceServer) CreateFlat(context.Context, *CreateFlatRequest) (*Flat, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFlat not implemented")
+}
+func (UnimplementedFlatServiceServer) SearchFlats(context.Context, *SearchFlatsRequest) (*SearchFlatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchFlats not implemented")
+}
+func (UnimplementedFlatServiceServer) mustEmbedUnimplementedFlatServiceServer() {}
+func (UnimplementedFlatServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeFlatServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlatServiceServer will
+// result in compilation errors.
+type UnsafeFlatServiceServer interface {
+	mustEmbedUnimplementedFlatServiceServer()
+}
+
+func RegisterFlatServiceServer(s grpc.ServiceRegistrar, srv FlatServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFlatServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FlatService_ServiceDesc, srv)
+}
+
+func _FlatService_CreateFlat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFlatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlatServiceServer).CreateFlat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlatService_CreateFlat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlatServiceServer).CreateFlat(ctx, req.(*CreateFlatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlatService_SearchFlats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchFlatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlatServiceServer).SearchFlats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlatService_SearchFlats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlatServiceServer).SearchFlats(ctx, req.(*SearchFlatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FlatService_ServiceDesc is the grpc.ServiceDesc for FlatService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FlatService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "housing.v1.FlatService",
+	HandlerType: (*FlatServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateFlat",
+			Handler:    _FlatService_CreateFlat_Handler,
+		},
+		{
+			MethodName: "SearchFlats",
+			Handler:    _FlatService_SearchFlats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "housing/v1/flat.proto",
+}
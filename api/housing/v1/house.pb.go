@@ -0,0 +1,542 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: housing/v1/house.proto
+
+package housingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type House struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Year          int32                  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	Developer     string                 `protobuf:"bytes,4,opt,name=developer,proto3" json:"developer,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *House) Reset() {
+	*x = House{}
+	mi := &file_housing_v1_house_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *House) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*House) ProtoMessage() {}
+
+func (x *House) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_house_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use House.ProtoReflect.Descriptor instead.
+func (*House) Descriptor() ([]byte, []int) {
+	return file_housing_v1_house_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *House) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *House) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *House) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *House) GetDeveloper() string {
+	if x != nil {
+		return x.Developer
+	}
+	return ""
+}
+
+func (x *House) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *House) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateHouseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Year          int32                  `protobuf:"varint,2,opt,name=year,proto3" json:"year,omitempty"`
+	Developer     string                 `protobuf:"bytes,3,opt,name=developer,proto3" json:"developer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateHouseRequest) Reset() {
+	*x = CreateHouseRequest{}
+	mi := &file_housing_v1_house_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateHouseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateHouseRequest) ProtoMessage() {}
+
+func (x *CreateHouseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_house_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateHouseRequest.ProtoReflect.Descriptor instead.
+func (*CreateHouseRequest) Descriptor() ([]byte, []int) {
+	return file_housing_v1_house_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateHouseRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *CreateHouseRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *CreateHouseRequest) GetDeveloper() string {
+	if x != nil {
+		return x.Developer
+	}
+	return ""
+}
+
+type SearchHousesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHousesRequest) Reset() {
+	*x = SearchHousesRequest{}
+	mi := &file_housing_v1_house_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHousesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHousesRequest) ProtoMessage() {}
+
+func (x *SearchHousesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_house_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHousesRequest.ProtoReflect.Descriptor instead.
+func (*SearchHousesRequest) Descriptor() ([]byte, []int) {
+	return file_housing_v1_house_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SearchHousesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchHousesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchHousesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Houses        []*House               `protobuf:"bytes,1,rep,name=houses,proto3" json:"houses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHousesResponse) Reset() {
+	*x = SearchHousesResponse{}
+	mi := &file_housing_v1_house_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHousesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHousesResponse) ProtoMessage() {}
+
+func (x *SearchHousesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_house_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHousesResponse.ProtoReflect.Descriptor instead.
+func (*SearchHousesResponse) Descriptor() ([]byte, []int) {
+	return file_housing_v1_house_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SearchHousesResponse) GetHouses() []*House {
+	if x != nil {
+		return x.Houses
+	}
+	return nil
+}
+
+// ListHouseFlatsRequest filters are ignored when zero.
+type ListHouseFlatsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	HouseId int64                  `protobuf:"varint,1,opt,name=house_id,json=houseId,proto3" json:"house_id,omitempty"`
+	// Cursor is the next_cursor of the previous page.
+	Cursor string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Limit is 100 when zero.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Sort is one of number, price or rooms.
+	Sort string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	// Order is asc or desc.
+	Order string `protobuf:"bytes,5,opt,name=order,proto3" json:"order,omitempty"`
+	// Status is only honoured for moderators.
+	Status        FlatStatus `protobuf:"varint,6,opt,name=status,proto3,enum=housing.v1.FlatStatus" json:"status,omitempty"`
+	Rooms         int32      `protobuf:"varint,7,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	PriceFrom     int32      `protobuf:"varint,8,opt,name=price_from,json=priceFrom,proto3" json:"price_from,omitempty"`
+	PriceTo       int32      `protobuf:"varint,9,opt,name=price_to,json=priceTo,proto3" json:"price_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHouseFlatsRequest) Reset() {
+	*x = ListHouseFlatsRequest{}
+	mi := &file_housing_v1_house_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHouseFlatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHouseFlatsRequest) ProtoMessage() {}
+
+func (x *ListHouseFlatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_house_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHouseFlatsRequest.ProtoReflect.Descriptor instead.
+func (*ListHouseFlatsRequest) Descriptor() ([]byte, []int) {
+	return file_housing_v1_house_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListHouseFlatsRequest) GetHouseId() int64 {
+	if x != nil {
+		return x.HouseId
+	}
+	return 0
+}
+
+func (x *ListHouseFlatsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListHouseFlatsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListHouseFlatsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListHouseFlatsRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *ListHouseFlatsRequest) GetStatus() FlatStatus {
+	if x != nil {
+		return x.Status
+	}
+	return FlatStatus_FLAT_STATUS_UNSPECIFIED
+}
+
+func (x *ListHouseFlatsRequest) GetRooms() int32 {
+	if x != nil {
+		return x.Rooms
+	}
+	return 0
+}
+
+func (x *ListHouseFlatsRequest) GetPriceFrom() int32 {
+	if x != nil {
+		return x.PriceFrom
+	}
+	return 0
+}
+
+func (x *ListHouseFlatsRequest) GetPriceTo() int32 {
+	if x != nil {
+		return x.PriceTo
+	}
+	return 0
+}
+
+type ListHouseFlatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Flats []*Flat                `protobuf:"bytes,1,rep,name=flats,proto3" json:"flats,omitempty"`
+	// NextCursor is empty on the last page.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHouseFlatsResponse) Reset() {
+	*x = ListHouseFlatsResponse{}
+	mi := &file_housing_v1_house_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHouseFlatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHouseFlatsResponse) ProtoMessage() {}
+
+func (x *ListHouseFlatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_house_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHouseFlatsResponse.ProtoReflect.Descriptor instead.
+func (*ListHouseFlatsResponse) Descriptor() ([]byte, []int) {
+	return file_housing_v1_house_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListHouseFlatsResponse) GetFlats() []*Flat {
+	if x != nil {
+		return x.Flats
+	}
+	return nil
+}
+
+func (x *ListHouseFlatsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+var File_housing_v1_house_proto protoreflect.FileDescriptor
+
+const file_housing_v1_house_proto_rawDesc = "" +
+	"\n" +
+	"\x16housing/v1/house.proto\x12\n" +
+	"housing.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x15housing/v1/flat.proto\"\xd9\x01\n" +
+	"\x05House\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04year\x18\x03 \x01(\x05R\x04year\x12\x1c\n" +
+	"\tdeveloper\x18\x04 \x01(\tR\tdeveloper\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"`\n" +
+	"\x12CreateHouseRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04year\x18\x02 \x01(\x05R\x04year\x12\x1c\n" +
+	"\tdeveloper\x18\x03 \x01(\tR\tdeveloper\"A\n" +
+	"\x13SearchHousesRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"A\n" +
+	"\x14SearchHousesResponse\x12)\n" +
+	"\x06houses\x18\x01 \x03(\v2\x11.housing.v1.HouseR\x06houses\"\x8a\x02\n" +
+	"\x15ListHouseFlatsRequest\x12\x19\n" +
+	"\bhouse_id\x18\x01 \x01(\x03R\ahouseId\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\x05 \x01(\tR\x05order\x12.\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x16.housing.v1.FlatStatusR\x06status\x12\x14\n" +
+	"\x05rooms\x18\a \x01(\x05R\x05rooms\x12\x1d\n" +
+	"\n" +
+	"price_from\x18\b \x01(\x05R\tpriceFrom\x12\x19\n" +
+	"\bprice_to\x18\t \x01(\x05R\apriceTo\"a\n" +
+	"\x16ListHouseFlatsResponse\x12&\n" +
+	"\x05flats\x18\x01 \x03(\v2\x10.housing.v1.FlatR\x05flats\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor2\xfc\x01\n" +
+	"\fHouseService\x12@\n" +
+	"\vCreateHouse\x12\x1e.housing.v1.CreateHouseRequest\x1a\x11.housing.v1.House\x12Q\n" +
+	"\fSearchHouses\x12\x1f.housing.v1.SearchHousesRequest\x1a .housing.v1.SearchHousesResponse\x12W\n" +
+	"\x0eListHouseFlats\x12!.housing.v1.ListHouseFlatsRequest\x1a\".housing.v1.ListHouseFlatsResponseBKZIgithub.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1;housingv1b\x06proto3"
+
+var (
+	file_housing_v1_house_proto_rawDescOnce sync.Once
+	file_housing_v1_house_proto_rawDescData []byte
+)
+
+func file_housing_v1_house_proto_rawDescGZIP() []byte {
+	file_housing_v1_house_proto_rawDescOnce.Do(func() {
+		file_housing_v1_house_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_housing_v1_house_proto_rawDesc), len(file_housing_v1_house_proto_rawDesc)))
+	})
+	return file_housing_v1_house_proto_rawDescData
+}
+
+var file_housing_v1_house_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_housing_v1_house_proto_goTypes = []any{
+	(*House)(nil),                  // 0: housing.v1.House
+	(*CreateHouseRequest)(nil),     // 1: housing.v1.CreateHouseRequest
+	(*SearchHousesRequest)(nil),    // 2: housing.v1.SearchHousesRequest
+	(*SearchHousesResponse)(nil),   // 3: housing.v1.SearchHousesResponse
+	(*ListHouseFlatsRequest)(nil),  // 4: housing.v1.ListHouseFlatsRequest
+	(*ListHouseFlatsResponse)(nil), // 5: housing.v1.ListHouseFlatsResponse
+	(*timestamppb.Timestamp)(nil),  // 6: google.protobuf.Timestamp
+	(FlatStatus)(0),                // 7: housing.v1.FlatStatus
+	(*Flat)(nil),                   // 8: housing.v1.Flat
+}
+var file_housing_v1_house_proto_depIdxs = []int32{
+	6, // 0: housing.v1.House.created_at:type_name -> google.protobuf.Timestamp
+	6, // 1: housing.v1.House.updated_at:type_name -> google.protobuf.Timestamp
+	0, // 2: housing.v1.SearchHousesResponse.houses:type_name -> housing.v1.House
+	7, // 3: housing.v1.ListHouseFlatsRequest.status:type_name -> housing.v1.FlatStatus
+	8, // 4: housing.v1.ListHouseFlatsResponse.flats:type_name -> housing.v1.Flat
+	1, // 5: housing.v1.HouseService.CreateHouse:input_type -> housing.v1.CreateHouseRequest
+	2, // 6: housing.v1.HouseService.SearchHouses:input_type -> housing.v1.SearchHousesRequest
+	4, // 7: housing.v1.HouseService.ListHouseFlats:input_type -> housing.v1.ListHouseFlatsRequest
+	0, // 8: housing.v1.HouseService.CreateHouse:output_type -> housing.v1.House
+	3, // 9: housing.v1.HouseService.SearchHouses:output_type -> housing.v1.SearchHousesResponse
+	5, // 10: housing.v1.HouseService.ListHouseFlats:output_type -> housing.v1.ListHouseFlatsResponse
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_housing_v1_house_proto_init() }
+func file_housing_v1_house_proto_init() {
+	if File_housing_v1_house_proto != nil {
+		return
+	}
+	file_housing_v1_flat_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_housing_v1_house_proto_rawDesc), len(file_housing_v1_house_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_housing_v1_house_proto_goTypes,
+		DependencyIndexes: file_housing_v1_house_proto_depIdxs,
+		MessageInfos:      file_housing_v1_house_proto_msgTypes,
+	}.Build()
+	File_housing_v1_house_proto = out.File
+	file_housing_v1_house_proto_goTypes = nil
+	file_housing_v1_house_proto_depIdxs = nil
+}
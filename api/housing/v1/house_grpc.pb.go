@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: housing/v1/house.proto
+
+package housingv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	HouseService_CreateHouse_FullMethodName    = "/housing.v1.HouseService/CreateHouse"
+	HouseService_SearchHouses_FullMethodName   = "/housing.v1.HouseService/SearchHouses"
+	HouseService_ListHouseFlats_FullMethodName = "/housing.v1.HouseService/ListHouseFlats"
+)
+
+// HouseServiceClient is the client API for HouseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// HouseService manages houses and lists their flats.
+type HouseServiceClient interface {
+	// CreateHouse adds a house. Moderators only.
+	CreateHouse(ctx context.Context, in *CreateHouseRequest, opts ...grpc.CallOption) (*House, error)
+	// SearchHouses finds houses by address or developer.
+	SearchHouses(ctx context.Context, in *SearchHousesRequest, opts ...grpc.CallOption) (*SearchHousesResponse, error)
+	// ListHouseFlats lists the flats of a house a page at a time. Clients only
+	// see approved flats.
+	ListHouseFlats(ctx context.Context, in *ListHouseFlatsRequest, opts ...grpc.CallOption) (*ListHouseFlatsResponse, error)
+}
+
+type houseServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHouseServiceClient(cc grpc.ClientConnInterface) HouseServiceClient {
+	return &houseServiceClient{cc}
+}
+
+func (c *houseServiceClient) CreateHouse(ctx context.Context, in *CreateHouseRequest, opts ...grpc.CallOption) (*House, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(House)
+	err := c.cc.Invoke(ctx, HouseService_CreateHouse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *houseServiceClient) SearchHouses(ctx context.Context, in *SearchHousesRequest, opts ...grpc.CallOption) (*SearchHousesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchHousesResponse)
+	err := c.cc.Invoke(ctx, HouseService_SearchHouses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *houseServiceClient) ListHouseFlats(ctx context.Context, in *ListHouseFlatsRequest, opts ...grpc.CallOption) (*ListHouseFlatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHouseFlatsResponse)
+	err := c.cc.Invoke(ctx, HouseService_ListHouseFlats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HouseServiceServer is the server API for HouseService service.
+// All implementations must embed UnimplementedHouseServiceServer
+// for forward compatibility.
+//
+// HouseService manages houses and lists their flats.
+type HouseServiceServer interface {
+	// CreateHouse adds a house. Moderators only.
+	CreateHouse(context.Context, *CreateHouseRequest) (*House, error)
+	// SearchHouses finds houses by address or developer.
+	SearchHouses(context.Context, *SearchHousesRequest) (*SearchHousesResponse, error)
+	// ListHouseFlats lists the flats of a house a page at a time. Clients only
+	// see approved flats.
+	ListHouseFlats(context.Context, *ListHouseFlatsRequest) (*ListHouseFlatsResponse, error)
+	mustEmbedUnimplementedHouseServiceServer()
+}
+
+// UnimplementedHouseServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHouseServiceServer struct{}
+
+func (UnimplementedHouseServiceServer) CreateHouse(context.Context, *CreateHouseRequest) (*House, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateHouse not implemented")
+}
+func (UnimplementedHouseServiceServer) SearchHouses(context.Context, *SearchHousesRequest) (*SearchHousesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchHouses not implemented")
+}
+func (UnimplementedHouseServiceServer) ListHouseFlats(context.Context, *ListHouseFlatsRequest) (*ListHouseFlatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHouseFlats not implemented")
+}
+func (UnimplementedHouseServiceServer) mustEmbedUnimplementedHouseServiceServer() {}
+func (UnimplementedHouseServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeHouseServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HouseServiceServer will
+// result in compilation errors.
+type UnsafeHouseServiceServer interface {
+	mustEmbedUnimplementedHouseServiceServer()
+}
+
+func RegisterHouseServiceServer(s grpc.ServiceRegistrar, srv HouseServiceServer) {
+	// If the following call pancis, it indicates UnimplementedHouseServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HouseService_ServiceDesc, srv)
+}
+
+func _HouseService_CreateHouse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateHouseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HouseServiceServer).CreateHouse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HouseService_CreateHouse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HouseServiceServer).CreateHouse(ctx, req.(*CreateHouseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HouseService_SearchHouses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchHousesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HouseServiceServer).SearchHouses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HouseService_SearchHouses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HouseServiceServer).SearchHouses(ctx, req.(*SearchHousesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HouseService_ListHouseFlats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHouseFlatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HouseServiceServer).ListHouseFlats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HouseService_ListHouseFlats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HouseServiceServer).ListHouseFlats(ctx, req.(*ListHouseFlatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HouseService_ServiceDesc is the grpc.ServiceDesc for HouseService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HouseService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "housing.v1.HouseService",
+	HandlerType: (*HouseServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateHouse",
+			Handler:    _HouseService_CreateHouse_Handler,
+		},
+		{
+			MethodName: "SearchHouses",
+			Handler:    _HouseService_SearchHouses_Handler,
+		},
+		{
+			MethodName: "ListHouseFlats",
+			Handler:    _HouseService_ListHouseFlats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "housing/v1/house.proto",
+}
@@ -0,0 +1,140 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: housing/v1/moderation.proto
+
+package housingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UpdateFlatStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        FlatStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=housing.v1.FlatStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFlatStatusRequest) Reset() {
+	*x = UpdateFlatStatusRequest{}
+	mi := &file_housing_v1_moderation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFlatStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlatStatusRequest) ProtoMessage() {}
+
+func (x *UpdateFlatStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_moderation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlatStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFlatStatusRequest) Descriptor() ([]byte, []int) {
+	return file_housing_v1_moderation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UpdateFlatStatusRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateFlatStatusRequest) GetStatus() FlatStatus {
+	if x != nil {
+		return x.Status
+	}
+	return FlatStatus_FLAT_STATUS_UNSPECIFIED
+}
+
+var File_housing_v1_moderation_proto protoreflect.FileDescriptor
+
+const file_housing_v1_moderation_proto_rawDesc = "" +
+	"\n" +
+	"\x1bhousing/v1/moderation.proto\x12\n" +
+	"housing.v1\x1a\x15housing/v1/flat.proto\"Y\n" +
+	"\x17UpdateFlatStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.housing.v1.FlatStatusR\x06status2^\n" +
+	"\x11ModerationService\x12I\n" +
+	"\x10UpdateFlatStatus\x12#.housing.v1.UpdateFlatStatusRequest\x1a\x10.housing.v1.FlatBKZIgithub.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1;housingv1b\x06proto3"
+
+var (
+	file_housing_v1_moderation_proto_rawDescOnce sync.Once
+	file_housing_v1_moderation_proto_rawDescData []byte
+)
+
+func file_housing_v1_moderation_proto_rawDescGZIP() []byte {
+	file_housing_v1_moderation_proto_rawDescOnce.Do(func() {
+		file_housing_v1_moderation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_housing_v1_moderation_proto_rawDesc), len(file_housing_v1_moderation_proto_rawDesc)))
+	})
+	return file_housing_v1_moderation_proto_rawDescData
+}
+
+var file_housing_v1_moderation_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_housing_v1_moderation_proto_goTypes = []any{
+	(*UpdateFlatStatusRequest)(nil), // 0: housing.v1.UpdateFlatStatusRequest
+	(FlatStatus)(0),                 // 1: housing.v1.FlatStatus
+	(*Flat)(nil),                    // 2: housing.v1.Flat
+}
+var file_housing_v1_moderation_proto_depIdxs = []int32{
+	1, // 0: housing.v1.UpdateFlatStatusRequest.status:type_name -> housing.v1.FlatStatus
+	0, // 1: housing.v1.ModerationService.UpdateFlatStatus:input_type -> housing.v1.UpdateFlatStatusRequest
+	2, // 2: housing.v1.ModerationService.UpdateFlatStatus:output_type -> housing.v1.Flat
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_housing_v1_moderation_proto_init() }
+func file_housing_v1_moderation_proto_init() {
+	if File_housing_v1_moderation_proto != nil {
+		return
+	}
+	file_housing_v1_flat_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_housing_v1_moderation_proto_rawDesc), len(file_housing_v1_moderation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_housing_v1_moderation_proto_goTypes,
+		DependencyIndexes: file_housing_v1_moderation_proto_depIdxs,
+		MessageInfos:      file_housing_v1_moderation_proto_msgTypes,
+	}.Build()
+	File_housing_v1_moderation_proto = out.File
+	file_housing_v1_moderation_proto_goTypes = nil
+	file_housing_v1_moderation_proto_depIdxs = nil
+}
@@ -0,0 +1,174 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: housing/v1/subscription.proto
+
+package housingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HouseId       int64                  `protobuf:"varint,1,opt,name=house_id,json=houseId,proto3" json:"house_id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_housing_v1_subscription_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_subscription_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_housing_v1_subscription_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubscribeRequest) GetHouseId() int64 {
+	if x != nil {
+		return x.HouseId
+	}
+	return 0
+}
+
+func (x *SubscribeRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type SubscribeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeResponse) Reset() {
+	*x = SubscribeResponse{}
+	mi := &file_housing_v1_subscription_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeResponse) ProtoMessage() {}
+
+func (x *SubscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_housing_v1_subscription_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeResponse) Descriptor() ([]byte, []int) {
+	return file_housing_v1_subscription_proto_rawDescGZIP(), []int{1}
+}
+
+var File_housing_v1_subscription_proto protoreflect.FileDescriptor
+
+const file_housing_v1_subscription_proto_rawDesc = "" +
+	"\n" +
+	"\x1dhousing/v1/subscription.proto\x12\n" +
+	"housing.v1\"C\n" +
+	"\x10SubscribeRequest\x12\x19\n" +
+	"\bhouse_id\x18\x01 \x01(\x03R\ahouseId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\"\x13\n" +
+	"\x11SubscribeResponse2_\n" +
+	"\x13SubscriptionService\x12H\n" +
+	"\tSubscribe\x12\x1c.housing.v1.SubscribeRequest\x1a\x1d.housing.v1.SubscribeResponseBKZIgithub.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1;housingv1b\x06proto3"
+
+var (
+	file_housing_v1_subscription_proto_rawDescOnce sync.Once
+	file_housing_v1_subscription_proto_rawDescData []byte
+)
+
+func file_housing_v1_subscription_proto_rawDescGZIP() []byte {
+	file_housing_v1_subscription_proto_rawDescOnce.Do(func() {
+		file_housing_v1_subscription_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_housing_v1_subscription_proto_rawDesc), len(file_housing_v1_subscription_proto_rawDesc)))
+	})
+	return file_housing_v1_subscription_proto_rawDescData
+}
+
+var file_housing_v1_subscription_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_housing_v1_subscription_proto_goTypes = []any{
+	(*SubscribeRequest)(nil),  // 0: housing.v1.SubscribeRequest
+	(*SubscribeResponse)(nil), // 1: housing.v1.SubscribeResponse
+}
+var file_housing_v1_subscription_proto_depIdxs = []int32{
+	0, // 0: housing.v1.SubscriptionService.Subscribe:input_type -> housing.v1.SubscribeRequest
+	1, // 1: housing.v1.SubscriptionService.Subscribe:output_type -> housing.v1.SubscribeResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_housing_v1_subscription_proto_init() }
+func file_housing_v1_subscription_proto_init() {
+	if File_housing_v1_subscription_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_housing_v1_subscription_proto_rawDesc), len(file_housing_v1_subscription_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_housing_v1_subscription_proto_goTypes,
+		DependencyIndexes: file_housing_v1_subscription_proto_depIdxs,
+		MessageInfos:      file_housing_v1_subscription_proto_msgTypes,
+	}.Build()
+	File_housing_v1_subscription_proto = out.File
+	file_housing_v1_subscription_proto_goTypes = nil
+	file_housing_v1_subscription_proto_depIdxs = nil
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/configs"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/bulk"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/outbox"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const adminUsage = `Usage: backend-bootcamp admin <command> [args] [flags]
+
+  user create <email> <client|moderator>  create a user, the password is read from stdin
+  user promote <id>                       make the user a moderator
+  user demote <id>                        make the user a client
+  user role <id> <role>                   give the user any role, e.g. admin
+  user roles                              list the roles with their permissions
+  user reset-password <id>                set the password read from stdin
+  flats list [status]                     list the flats with the status, on_moderate by default
+  flats moderate <status> <id>...         set the status of the flats
+  import <file> [dry-run] [chunk-size=N]  import houses and flats from a .csv or .ndjson file
+  notifications redrive [id]...           retry failed notifications, all of them without ids
+  keys rotate                             generate a new signing key
+  config                                  print the resolved config with secrets redacted
+
+Flags are the same as for the service and go after the arguments.`
+
+var errAdminUsage = errors.New("invalid arguments, run admin without arguments for usage")
+
+// adminArgs splits the arguments of an admin command from the config flags
+// following them.
+func adminArgs(args []string) (command, flags []string) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return args[:i], args[i:]
+		}
+	}
+	return args, nil
+}
+
+func admin(ctx context.Context, config configs.Config, args []string) error {
+	switch args[0] {
+	case "config":
+		return printConfig(config)
+	case "keys":
+		if len(args) != 2 || args[1] != "rotate" {
+			return errAdminUsage
+		}
+		return rotateKeys(config.Auth)
+	case "user", "flats", "notifications", "import":
+		if len(args) < 2 {
+			return errAdminUsage
+		}
+	default:
+		return fmt.Errorf("unknown admin command %q", args[0])
+	}
+
+	db, err := postgres.NewDB(ctx, config.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "user":
+		return adminUser(ctx, auth.NewRepo(db, auth.NewTokenManager(config.Auth)), args[1], args[2:])
+	case "flats":
+		return adminFlats(ctx, flat.NewRepo(db), args[1], args[2:])
+	case "import":
+		return adminImport(ctx, bulk.NewRepo(db), args[1], args[2:])
+	default:
+		return adminNotifications(ctx, outbox.NewRepo(db), args[1], args[2:])
+	}
+}
+
+func adminUser(ctx context.Context, repo *auth.Repo, command string, args []string) error {
+	switch command {
+	case "create":
+		if len(args) != 2 {
+			return errAdminUsage
+		}
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+
+		request := usecase.CreateUserRequest{Email: args[0], Password: password, UserType: args[1]}
+		if err = request.Validate(); err != nil {
+			return err
+		}
+		response, err := repo.Register(ctx, request)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %s %d\n", request.UserType, response.UserId)
+		return nil
+	case "promote", "demote":
+		ids, err := parseIDs(args)
+		if err != nil || len(ids) != 1 {
+			return errAdminUsage
+		}
+
+		userType := "moderator"
+		if command == "demote" {
+			userType = "client"
+		}
+		if err = repo.SetUserType(ctx, ids[0], userType); err != nil {
+			return err
+		}
+		fmt.Printf("User %d is a %s now, tokens issued before keep the old role until they expire\n", ids[0], userType)
+		return nil
+	case "role":
+		if len(args) != 2 {
+			return errAdminUsage
+		}
+		ids, err := parseIDs(args[:1])
+		if err != nil {
+			return errAdminUsage
+		}
+
+		request := usecase.SetRoleRequest{UserID: ids[0], Role: args[1]}
+		if err = request.Validate(); err != nil {
+			return err
+		}
+		if err = repo.SetUserType(ctx, request.UserID, request.Role); err != nil {
+			return err
+		}
+		fmt.Printf("User %d is a %s now, tokens issued before keep the old role until they expire\n", request.UserID, request.Role)
+		return nil
+	case "roles":
+		if len(args) != 0 {
+			return errAdminUsage
+		}
+		roles, err := repo.Roles(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ROLE\tPERMISSIONS")
+		for _, role := range roles {
+			permissions := make([]string, len(role.Permissions))
+			for i, permission := range role.Permissions {
+				permissions[i] = string(permission)
+			}
+			fmt.Fprintf(w, "%s\t%s\n", role.Name, strings.Join(permissions, " "))
+		}
+		return w.Flush()
+	case "reset-password":
+		ids, err := parseIDs(args)
+		if err != nil || len(ids) != 1 {
+			return errAdminUsage
+		}
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+
+		request := usecase.PasswordResetRequest{ID: ids[0], Password: password}
+		if err = request.Validate(); err != nil {
+			return err
+		}
+		if err = repo.ResetPassword(ctx, request); err != nil {
+			return err
+		}
+		fmt.Printf("Password of user %d is reset\n", request.ID)
+		return nil
+	default:
+		return fmt.Errorf("unknown admin user command %q", command)
+	}
+}
+
+func adminFlats(ctx context.Context, repo *flat.Repo, command string, args []string) error {
+	switch command {
+	case "list":
+		status := "on_moderate"
+		if len(args) > 1 {
+			return errAdminUsage
+		}
+		if len(args) == 1 {
+			status = args[0]
+		}
+
+		flats, err := repo.FlatsByStatus(ctx, status)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tHOUSE\tNUMBER\tPRICE\tROOMS\tSTATUS")
+		for _, f := range flats {
+			fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%s\n", f.ID, f.HouseID, f.Number, f.Price, f.Rooms, f.Status)
+		}
+		return w.Flush()
+	case "moderate":
+		if len(args) < 2 {
+			return errAdminUsage
+		}
+		ids, err := parseIDs(args[1:])
+		if err != nil {
+			return err
+		}
+
+		if err = (usecase.FlatUpdateRequest{ID: ids[0], Status: args[0]}).Validate(); err != nil {
+			return err
+		}
+
+		// Every flat is updated on its own, like through /flat/update, so one
+		// failure does not roll back the others
+		failed := 0
+		for _, id := range ids {
+			if _, err = repo.Update(ctx, usecase.FlatUpdateRequest{ID: id, Status: args[0]}); err != nil {
+				failed++
+				fmt.Printf("FAIL %d: %v\n", id, err)
+				continue
+			}
+			fmt.Printf("OK   %d\n", id)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d flats not moderated", failed, len(ids))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown admin flats command %q", command)
+	}
+}
+
+func adminNotifications(ctx context.Context, repo *outbox.Repo, command string, args []string) error {
+	if command != "redrive" {
+		return fmt.Errorf("unknown admin notifications command %q", command)
+	}
+
+	ids, err := parseIDs(args)
+	if err != nil {
+		return err
+	}
+
+	count, err := repo.Redrive(ctx, ids)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d notifications will be retried\n", count)
+	return nil
+}
+
+func adminImport(ctx context.Context, repo *bulk.Repo, path string, args []string) error {
+	var options usecase.ImportOptions
+	for _, arg := range args {
+		if arg == "dry-run" {
+			options.DryRun = true
+			continue
+		}
+		size, ok := strings.CutPrefix(arg, "chunk-size=")
+		if !ok {
+			return errAdminUsage
+		}
+		var err error
+		if options.ChunkSize, err = strconv.Atoi(size); err != nil {
+			return fmt.Errorf("invalid chunk size %q", size)
+		}
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	format := bulk.CSV
+	switch filepath.Ext(path) {
+	case ".csv":
+	case ".ndjson", ".jsonl":
+		format = bulk.NDJSON
+	default:
+		return fmt.Errorf("unknown format of %s, expected .csv or .ndjson", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rows, err := bulk.Parse(file, format)
+	if err != nil {
+		return err
+	}
+
+	report, err := repo.Import(ctx, rows, options)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		if row.Err != nil {
+			fmt.Printf("FAIL line %d: %s\n", row.Line, bulk.Errors.From(row.Err).Message)
+		}
+	}
+	verb := "Imported"
+	if report.DryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d of %d rows\n", verb, report.Imported, report.Total)
+	if report.Aborted {
+		return fmt.Errorf("import aborted after %d rows, see the service logs", report.Imported)
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d rows not imported", report.Failed)
+	}
+	return nil
+}
+
+func rotateKeys(config auth.Config) error {
+	rotated, err := config.Rotate()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deploy the new key, tokens signed with the previous one stay valid for %s:\n\n", config.TokenTTL)
+	fmt.Printf("JWT_SECRET=%s\n", rotated.Secret)
+	fmt.Printf("JWT_PREVIOUS_SECRETS=%s\n", strings.Join(rotated.PreviousSecrets, ","))
+	return nil
+}
+
+func printConfig(config configs.Config) error {
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config.Redacted()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", errors.New("no password on stdin")
+	}
+	return strings.TrimRight(scanner.Text(), "\r"), nil
+}
+
+func parseIDs(args []string) ([]int, error) {
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid id %q", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -1,16 +1,116 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/configs"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/app"
-	"log"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/database"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"log/slog"
+	"os"
+	"strings"
 )
 
+const usage = `Usage:
+  backend-bootcamp [flags]                                 run the service
+  backend-bootcamp migrate up|down|status|version [flags]  manage the database schema
+  backend-bootcamp admin <command> [args] [flags]          run an operational command, see admin without arguments
+
+Run with -h to list the flags.`
+
 func main() {
-	config, err := configs.FromEnv()
+	args := os.Args[1:]
+	command := ""
+	var adminCommand []string
+	switch {
+	case len(args) > 0 && args[0] == "migrate":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(2)
+		}
+		command, args = args[1], args[2:]
+	case len(args) > 0 && args[0] == "admin":
+		adminCommand, args = adminArgs(args[1:])
+		if len(adminCommand) == 0 {
+			fmt.Fprintln(os.Stderr, adminUsage)
+			os.Exit(2)
+		}
+	}
+
+	config, err := configs.Load(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		slog.Error("Failed to load config", "error", err)
+		os.Exit(2)
+	}
+
+	slog.SetDefault(logger.New(os.Stdout, config.Log.Level))
+
+	if adminCommand != nil {
+		if err = admin(context.Background(), config, adminCommand); err != nil {
+			slog.Error("Admin command failed", "command", strings.Join(adminCommand, " "), "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if command == "" {
+		app.Run(config)
+		return
+	}
+
+	if err = migrate(context.Background(), config, command); err != nil {
+		slog.Error("Migration failed", "command", command, "error", err)
+		os.Exit(1)
+	}
+}
+
+func migrate(ctx context.Context, config configs.Config, command string) error {
+	migrator, err := database.NewMigrator(config.Database)
 	if err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
+		return err
 	}
+	defer migrator.Close()
 
-	app.Run(config)
+	switch command {
+	case "up":
+		results, err := migrator.Up(ctx)
+		for _, result := range results {
+			fmt.Printf("OK   %s (%s)\n", result.Source.Path, result.Duration)
+		}
+		return err
+	case "down":
+		result, err := migrator.Down(ctx)
+		if result != nil {
+			fmt.Printf("OK   %s (%s)\n", result.Source.Path, result.Duration)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "Pending"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-19s -- %s\n", appliedAt, status.Source.Path)
+		}
+		return nil
+	case "version":
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(version)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down, status or version", command)
+	}
 }
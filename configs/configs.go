@@ -1,29 +1,335 @@
 package configs
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/graph"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/bulk"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/tracing"
+	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+	"io/fs"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
-const (
-	dbHost     = "DB_HOST"
-	dbPort     = "DB_PORT"
-	dbUser     = "DB_USER"
-	dbPassword = "DB_PASSWORD"
-	dbName     = "DB_NAME"
-)
+const configFile = "CONFIG_FILE"
+
+// Config is the whole service configuration. Values are resolved with the
+// precedence defaults < YAML file < environment (including .env) < flags.
+type Config struct {
+	Server   Server                  `yaml:"server"`
+	Database postgres.DatabaseConfig `yaml:"database"`
+	Migrate  Migrate                 `yaml:"migrate"`
+	Auth     auth.Config             `yaml:"auth"`
+	Sender   sender.Config           `yaml:"sender"`
+	GraphQL  graph.Config            `yaml:"graphql"`
+	Import   bulk.Config             `yaml:"import"`
+	Log      Log                     `yaml:"log"`
+	Tracing  tracing.Config          `yaml:"tracing"`
+}
+
+type Server struct {
+	Addr              string        `yaml:"addr" validate:"required"`
+	GRPCAddr          string        `yaml:"grpc_addr" validate:"required"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" validate:"gt=0"`
+	// DrainDelay is how long /readyz reports 503 before the listener is closed.
+	DrainDelay      time.Duration `yaml:"drain_delay" validate:"gte=0"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" validate:"gt=0"`
+}
+
+type Migrate struct {
+	// OnStart applies pending migrations before the server starts serving.
+	OnStart bool `yaml:"on_start"`
+}
+
+type Log struct {
+	Level string `yaml:"level" validate:"omitempty,oneof=debug info warn warning error"`
+}
+
+func Default() Config {
+	return Config{
+		Server: Server{
+			Addr:              ":8080",
+			GRPCAddr:          ":9090",
+			ReadHeaderTimeout: 5 * time.Second,
+			DrainDelay:        5 * time.Second,
+			ShutdownTimeout:   15 * time.Second,
+		},
+		Database: postgres.DatabaseConfig{
+			Host:              "localhost",
+			Port:              "5432",
+			SSLMode:           "disable",
+			MaxConns:          10,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+			StatementTimeout:  15 * time.Second,
+			QueryTimeout:      10 * time.Second,
+		},
+		Auth: auth.Config{
+			TokenTTL: 24 * time.Hour,
+			Issuer:   "my-app",
+		},
+		Sender: sender.Config{
+			PollInterval: time.Second,
+			BatchSize:    20,
+			Lease:        time.Minute,
+			MaxAttempts:  5,
+			RetryBackoff: 30 * time.Second,
+			DrainTimeout: 10 * time.Second,
+		},
+		GraphQL: graph.Config{
+			MaxDepth:      8,
+			MaxComplexity: 1000,
+		},
+		Import: bulk.Config{
+			MaxBytes: 32 << 20,
+		},
+		Log: Log{
+			Level: "info",
+		},
+		Tracing: tracing.Config{
+			Exporter:    tracing.ExporterNone,
+			ServiceName: "backend-bootcamp",
+		},
+	}
+}
+
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	value any
+}
+
+func (config *Config) settings() []setting {
+	return []setting{
+		{"addr", "PORT", "HTTP listen address", &config.Server.Addr},
+		{"grpc-addr", "GRPC_ADDR", "gRPC listen address", &config.Server.GRPCAddr},
+		{"read-header-timeout", "READ_HEADER_TIMEOUT", "time allowed to read request headers", &config.Server.ReadHeaderTimeout},
+		{"drain-delay", "DRAIN_DELAY", "time /readyz fails before the listener is closed on shutdown", &config.Server.DrainDelay},
+		{"shutdown-timeout", "SHUTDOWN_TIMEOUT", "time allowed for in-flight requests on shutdown", &config.Server.ShutdownTimeout},
+		{"db-host", "DB_HOST", "PostgreSQL host", &config.Database.Host},
+		{"db-port", "DB_PORT", "PostgreSQL port", &config.Database.Port},
+		{"db-user", "DB_USER", "PostgreSQL user", &config.Database.User},
+		{"db-password", "DB_PASSWORD", "PostgreSQL password", &config.Database.Password},
+		{"db-name", "DB_NAME", "PostgreSQL database", &config.Database.Name},
+		{"db-replicas", "DB_REPLICAS", "comma-separated host[:port] of read replicas", &config.Database.Replicas},
+		{"db-ssl-mode", "DB_SSL_MODE", "disable, allow, prefer, require, verify-ca or verify-full", &config.Database.SSLMode},
+		{"db-max-conns", "DB_MAX_CONNS", "maximum pool size", &config.Database.MaxConns},
+		{"db-min-conns", "DB_MIN_CONNS", "minimum idle connections kept open", &config.Database.MinConns},
+		{"db-max-conn-lifetime", "DB_MAX_CONN_LIFETIME", "time after which a connection is recycled", &config.Database.MaxConnLifetime},
+		{"db-max-conn-idle-time", "DB_MAX_CONN_IDLE_TIME", "time after which an idle connection is closed", &config.Database.MaxConnIdleTime},
+		{"db-health-check-period", "DB_HEALTH_CHECK_PERIOD", "how often idle connections are checked", &config.Database.HealthCheckPeriod},
+		{"db-statement-timeout", "DB_STATEMENT_TIMEOUT", "server-side statement_timeout, 0 disables it", &config.Database.StatementTimeout},
+		{"db-query-timeout", "DB_QUERY_TIMEOUT", "client-side deadline per query, 0 disables it", &config.Database.QueryTimeout},
+		{"migrate-on-start", "MIGRATE_ON_START", "apply pending migrations on start", &config.Migrate.OnStart},
+		{"jwt-secret", "JWT_SECRET", "HS256 signing key for tokens", &config.Auth.Secret},
+		{"jwt-previous-secrets", "JWT_PREVIOUS_SECRETS", "comma-separated retired signing keys still accepted for verification", &config.Auth.PreviousSecrets},
+		{"jwt-ttl", "JWT_TOKEN_TTL", "token lifetime", &config.Auth.TokenTTL},
+		{"jwt-issuer", "JWT_ISSUER", "token issuer", &config.Auth.Issuer},
+		{"sender-poll-interval", "SENDER_POLL_INTERVAL", "how often the notification outbox is polled", &config.Sender.PollInterval},
+		{"sender-batch-size", "SENDER_BATCH_SIZE", "notifications claimed per poll", &config.Sender.BatchSize},
+		{"sender-lease", "SENDER_LEASE", "how long a claimed notification is reserved", &config.Sender.Lease},
+		{"sender-max-attempts", "SENDER_MAX_ATTEMPTS", "attempts before a notification is marked failed", &config.Sender.MaxAttempts},
+		{"sender-retry-backoff", "SENDER_RETRY_BACKOFF", "delay per attempt before a failed notification is retried", &config.Sender.RetryBackoff},
+		{"sender-drain-timeout", "SENDER_DRAIN_TIMEOUT", "time allowed for in-flight notifications on shutdown", &config.Sender.DrainTimeout},
+		{"graphql-max-depth", "GRAPHQL_MAX_DEPTH", "deepest field nesting of a GraphQL query", &config.GraphQL.MaxDepth},
+		{"graphql-max-complexity", "GRAPHQL_MAX_COMPLEXITY", "most fields a GraphQL query may resolve", &config.GraphQL.MaxComplexity},
+		{"import-max-bytes", "IMPORT_MAX_BYTES", "largest /v2/import file accepted, in bytes", &config.Import.MaxBytes},
+		{"log-level", "LOG_LEVEL", "debug, info, warn or error", &config.Log.Level},
+		{"trace-exporter", "OTEL_TRACES_EXPORTER", "none, otlp or console", &config.Tracing.Exporter},
+		{"service-name", "OTEL_SERVICE_NAME", "service name reported in traces", &config.Tracing.ServiceName},
+	}
+}
+
+// Load resolves the configuration from defaults, the YAML file given by -config
+// or CONFIG_FILE, the environment and args, and validates the result.
+// A missing .env file is not an error.
+func Load(args []string) (Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Config{}, fmt.Errorf("load .env: %w", err)
+	}
+
+	config := Default()
+	settings := config.settings()
+
+	flags := flag.NewFlagSet("backend-bootcamp", flag.ContinueOnError)
+	path := flags.String("config", os.Getenv(configFile), "path to YAML config file (env "+configFile+")")
+	overrides := make(map[string]string)
+	for _, s := range settings {
+		name := s.flag
+		usage := fmt.Sprintf("%s (env %s)", s.usage, s.env)
+		override := func(raw string) error {
+			overrides[name] = raw
+			return nil
+		}
+		if _, ok := s.value.(*bool); ok {
+			flags.BoolFunc(name, usage, override)
+		} else {
+			flags.Func(name, usage, override)
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if *path != "" {
+		if err := config.readFile(*path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	for _, s := range settings {
+		raw, ok := os.LookupEnv(s.env)
+		if !ok {
+			continue
+		}
+		if err := set(s.value, raw); err != nil {
+			return Config{}, fmt.Errorf("env %s: %w", s.env, err)
+		}
+	}
+
+	for _, s := range settings {
+		raw, ok := overrides[s.flag]
+		if !ok {
+			continue
+		}
+		if err := set(s.value, raw); err != nil {
+			return Config{}, fmt.Errorf("flag -%s: %w", s.flag, err)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
+const redacted = "REDACTED"
+
+// Redacted returns a copy of the config safe to print, with passwords and
+// signing keys replaced.
+func (config Config) Redacted() Config {
+	if config.Database.Password != "" {
+		config.Database.Password = redacted
+	}
+	if config.Auth.Secret != "" {
+		config.Auth.Secret = redacted
+	}
+
+	previous := make([]string, len(config.Auth.PreviousSecrets))
+	for i := range previous {
+		previous[i] = redacted
+	}
+	config.Auth.PreviousSecrets = previous
+
+	return config
+}
+
+func (config *Config) readFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(config); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate reports every invalid field by its YAML path, e.g. "auth.secret is required".
+func (config Config) Validate() error {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("yaml"), ",")[0]
+	})
+
+	err := validate.Struct(config)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	problems := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		name := strings.TrimPrefix(fieldErr.Namespace(), "Config.")
+		problems = append(problems, name+" "+describe(fieldErr))
+	}
+
+	return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+}
+
+func describe(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + fieldErr.Param()
+	case "numeric":
+		return "must be a number"
+	case "gt":
+		return "must be greater than " + fieldErr.Param()
+	case "gte":
+		return "must be at least " + fieldErr.Param()
+	default:
+		return fmt.Sprintf("failed the %q check", fieldErr.Tag())
+	}
+}
 
-func FromEnv() (postgres.DatabaseConfig, error) {
-	if err := godotenv.Load(); err != nil {
-		return postgres.DatabaseConfig{}, err
+func set(value any, raw string) error {
+	switch v := value.(type) {
+	case *string:
+		*v = raw
+	case *[]string:
+		*v = nil
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*v = append(*v, item)
+			}
+		}
+	case *int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		*v = n
+	case *int32:
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		*v = int32(n)
+	case *time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		*v = d
+	case *bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		*v = b
+	default:
+		return fmt.Errorf("unsupported setting type %T", value)
 	}
 
-	return postgres.DatabaseConfig{
-		Host:     os.Getenv(dbHost),
-		Port:     os.Getenv(dbPort),
-		User:     os.Getenv(dbUser),
-		Password: os.Getenv(dbPassword),
-		Name:     os.Getenv(dbName),
-	}, nil
+	return nil
 }
@@ -0,0 +1,84 @@
+package configs
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  host: file-host
+  user: file-user
+  name: file-db
+auth:
+  secret: file-secret
+sender:
+  batch_size: 5
+`)
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("SENDER_BATCH_SIZE", "7")
+	t.Setenv("DB_REPLICAS", "replica-1:5433, replica-2")
+
+	config, err := Load([]string{"-config", path, "-sender-batch-size", "9", "-jwt-ttl", "1h", "-migrate-on-start", "-db-max-conns", "25"})
+	require.NoError(t, err)
+
+	require.Equal(t, "env-host", config.Database.Host)
+	require.Equal(t, "file-user", config.Database.User)
+	require.Equal(t, "file-secret", config.Auth.Secret)
+	require.Equal(t, 9, config.Sender.BatchSize)
+	require.Equal(t, time.Hour, config.Auth.TokenTTL)
+	require.Equal(t, ":8080", config.Server.Addr)
+	require.Equal(t, ":9090", config.Server.GRPCAddr)
+	require.True(t, config.Migrate.OnStart)
+	require.EqualValues(t, 25, config.Database.MaxConns)
+	require.Equal(t, []string{"replica-1:5433", "replica-2"}, config.Database.Replicas)
+}
+
+func TestLoadValidation(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  user: test
+  name: test
+tracing:
+  exporter: zipkin
+`)
+
+	_, err := Load([]string{"-config", path})
+	require.ErrorContains(t, err, "auth.secret is required")
+	require.ErrorContains(t, err, "tracing.exporter must be one of: none otlp console")
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	_, err := Load([]string{"-sender-lease", "soon"})
+	require.ErrorContains(t, err, `flag -sender-lease: invalid duration "soon"`)
+
+	_, err = Load([]string{"-config", writeConfig(t, "server:\n  port: 8080\n")})
+	require.ErrorContains(t, err, "field port not found")
+}
+
+func TestRedacted(t *testing.T) {
+	config := Default()
+	config.Database.Password = "db-password"
+	config.Auth.Secret = "current-secret"
+	config.Auth.PreviousSecrets = []string{"old-secret"}
+
+	redacted := config.Redacted()
+	require.Equal(t, "REDACTED", redacted.Database.Password)
+	require.Equal(t, "REDACTED", redacted.Auth.Secret)
+	require.Equal(t, []string{"REDACTED"}, redacted.Auth.PreviousSecrets)
+	require.Equal(t, config.Database.Host, redacted.Database.Host)
+
+	// The original is left untouched
+	require.Equal(t, "current-secret", config.Auth.Secret)
+	require.Equal(t, []string{"old-secret"}, config.Auth.PreviousSecrets)
+}
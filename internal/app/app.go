@@ -2,58 +2,208 @@ package app
 
 import (
 	"context"
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/configs"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/database"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/metrics"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/graph"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/health"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/middleware"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/router"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/rpc"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/bulk"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/export"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/outbox"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
-	"log"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/tracing"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
+	"syscall"
+	"time"
 )
 
-const port = "PORT"
-
-func Run(config postgres.DatabaseConfig) {
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+func Run(config configs.Config) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	db, err := postgres.NewDB(ctx, config)
+	shutdownTracing, err := tracing.Setup(ctx, config.Tracing)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
 	}
 
-	defer db.GetPool().Close()
+	if config.Migrate.OnStart {
+		if err = migrate(ctx, config.Database); err != nil {
+			slog.Error("Failed to apply migrations", "error", err)
+			os.Exit(1)
+		}
+	}
 
-	auth := auth.NewHandler(db)
-	house := house.NewHandler(db)
-	flat := flat.NewHandler(db)
-	s := sender.NewHandler(db)
-	r := router.New(auth, house, flat, s)
+	db, err := postgres.NewDB(ctx, config.Database)
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+
+	defer db.Close()
+
+	if err = metrics.RegisterPool("primary", db.GetPool()); err != nil {
+		slog.Error("Failed to register pool metrics", "error", err)
+		os.Exit(1)
+	}
+	for address, pool := range db.ReplicaPools() {
+		if err = metrics.RegisterPool(address, pool); err != nil {
+			slog.Error("Failed to register pool metrics", "replica", address, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	worker := sender.New(outbox.NewRepo(db), config.Sender)
+	health := health.NewHandler(
+		health.Check{Name: "database", Check: db.GetPool().Ping},
+		health.Check{Name: "migrations", Check: func(ctx context.Context) error {
+			return database.CheckVersion(ctx, db)
+		}},
+		health.Check{Name: "sender", Check: worker.Check},
+	)
+
+	spec, err := api.Load()
+	if err != nil {
+		slog.Error("Failed to load API specification", "error", err)
+		os.Exit(1)
+	}
+	validate, err := middleware.RequestValidator(spec)
+	if err != nil {
+		slog.Error("Failed to build request validator", "error", err)
+		os.Exit(1)
+	}
+
+	tokens := auth.NewTokenManager(config.Auth)
+	houses := house.NewRepo(db)
+	flats := flat.NewRepo(db)
+	subscriptions := sender.NewRepo(db)
+	users := auth.NewRepo(db, tokens)
+	errs := slices.Concat(auth.Errors, sender.Errors, bulk.Errors)
+
+	graph, err := graph.NewHandler(config.GraphQL, errs, houses, flats, subscriptions, users)
+	if err != nil {
+		slog.Error("Failed to build GraphQL handler", "error", err)
+		os.Exit(1)
+	}
+
+	auth := auth.NewHandler(users, tokens)
+	house := house.NewHandler(houses)
+	flat := flat.NewHandler(flats)
+	s := sender.NewHandler(subscriptions)
+	imports := bulk.NewHandler(bulk.NewRepo(db), config.Import)
+	exports := export.NewHandler(export.NewRepo(db))
+	r := router.New(tokens, validate, errs, auth, house, flat, s, imports, exports, graph, health)
+
+	server := &http.Server{
+		Addr:              config.Server.Addr,
+		Handler:           r,
+		ReadHeaderTimeout: config.Server.ReadHeaderTimeout,
+	}
+
+	grpcServer := rpc.New(tokens, errs, houses, flats, subscriptions)
+	grpcListener, err := net.Listen("tcp", config.Server.GRPCAddr)
+	if err != nil {
+		slog.Error("Failed to listen for gRPC", "addr", config.Server.GRPCAddr, "error", err)
+		os.Exit(1)
+	}
 
-	port := os.Getenv(port)
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+
+		if err := worker.Run(ctx); err != nil {
+			slog.Error("Sender stopped with pending notifications", "error", err)
+			return
+		}
+		slog.Info("Sender stopped")
+	}()
 
-	sender := sender.New()
 	go func() {
 		defer wg.Done()
 
-		if err := sender.Run(); err != nil {
-			log.Fatal(err)
+		<-ctx.Done()
+		slog.Info("Shutting down, readiness probe is failing from now on")
+		health.Drain()
+
+		// Даём балансировщику время заметить неготовность, прежде чем закрывать соединения
+		time.Sleep(config.Server.DrainDelay)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Server.ShutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("Failed to shut down server gracefully", "error", err)
+		}
+
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			slog.Error("Failed to shut down gRPC server gracefully", "error", shutdownCtx.Err())
+			grpcServer.Stop()
 		}
 	}()
 
 	go func() {
 		defer wg.Done()
 
-		if err = http.ListenAndServe(port, r); err != nil {
-			log.Fatalf("Error starting server: %s", err)
+		slog.Info("Starting gRPC server", "addr", config.Server.GRPCAddr)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			slog.Error("Error serving gRPC", "error", err)
+			stop()
 		}
 	}()
 
+	slog.Info("Starting server", "addr", server.Addr)
+	if err = server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Error starting server", "error", err)
+		stop()
+	}
+
 	wg.Wait()
+	slog.Info("Server stopped")
+
+	// Пул закрывается отложенным вызовом последним, после сервера, воркера и трассировки
+	if err := shutdownTracing(context.Background()); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+}
+
+// migrate applies pending migrations. Replicas starting together wait on the
+// migrator's advisory lock, and all but the first find nothing to apply.
+func migrate(ctx context.Context, config postgres.DatabaseConfig) error {
+	migrator, err := database.NewMigrator(config)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	results, err := migrator.Up(ctx)
+	for _, result := range results {
+		slog.Info("Migration applied", "source", result.Source.Path, "duration", result.Duration)
+	}
+
+	return err
 }
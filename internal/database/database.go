@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const migrationsDir = "migrations"
+
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// LatestVersion returns the goose version of the newest embedded migration.
+func LatestVersion() (int64, error) {
+	entries, err := fs.ReadDir(Migrations, migrationsDir)
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(path.Base(entry.Name()), "_")
+		if !ok {
+			continue
+		}
+
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s: invalid version: %w", entry.Name(), err)
+		}
+		latest = max(latest, version)
+	}
+
+	return latest, nil
+}
+
+// CheckVersion reports an error unless the database schema is migrated to LatestVersion.
+func CheckVersion(ctx context.Context, db *postgres.Database) error {
+	expected, err := LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	var current int64
+	query := `SELECT coalesce(max(version_id), 0) FROM goose_db_version WHERE is_applied`
+	if err = db.Get(postgres.WithPrimary(ctx), &current, query); err != nil {
+		return err
+	}
+
+	if current != expected {
+		return fmt.Errorf("schema version %d, expected %d", current, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package database
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLatestVersion(t *testing.T) {
+	version, err := LatestVersion()
+	require.NoError(t, err)
+	require.Greater(t, version, int64(20240816111858), "init migration must not be the latest one")
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"io/fs"
+)
+
+// Migrator applies the embedded migrations. Every operation holds a PostgreSQL
+// advisory lock for its duration, so replicas migrating on start don't race.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+func NewMigrator(config postgres.DatabaseConfig) (*Migrator, error) {
+	db, err := sql.Open("pgx", postgres.GenerateDsn(config))
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := fs.Sub(Migrations, migrationsDir)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations, goose.WithSessionLocker(locker))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Migrator{provider: provider}, nil
+}
+
+func (migrator *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return migrator.provider.Up(ctx)
+}
+
+// Down rolls back the most recently applied migration.
+func (migrator *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return migrator.provider.Down(ctx)
+}
+
+func (migrator *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return migrator.provider.Status(ctx)
+}
+
+func (migrator *Migrator) Version(ctx context.Context) (int64, error) {
+	return migrator.provider.GetDBVersion(ctx)
+}
+
+// Close closes the migrator's own connection, it does not share the service pool.
+func (migrator *Migrator) Close() error {
+	return migrator.provider.Close()
+}
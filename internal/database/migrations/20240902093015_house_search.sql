@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE house
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(address, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(developer, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS house_search_vector_idx ON house USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS house_address_trgm_idx ON house USING GIN (address gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS house_developer_trgm_idx ON house USING GIN (developer gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS house_developer_trgm_idx;
+DROP INDEX IF EXISTS house_address_trgm_idx;
+DROP INDEX IF EXISTS house_search_vector_idx;
+ALTER TABLE house DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
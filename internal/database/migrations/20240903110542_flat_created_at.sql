@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE flat ADD COLUMN IF NOT EXISTS created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS flat_status_price_idx ON flat (status, price);
+CREATE INDEX IF NOT EXISTS flat_created_at_idx ON flat (created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS flat_created_at_idx;
+DROP INDEX IF EXISTS flat_status_price_idx;
+ALTER TABLE flat DROP COLUMN IF EXISTS created_at;
+-- +goose StatementEnd
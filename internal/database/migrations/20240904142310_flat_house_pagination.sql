@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS flat_house_id_price_idx ON flat (house_id, price, id);
+CREATE INDEX IF NOT EXISTS flat_house_id_rooms_idx ON flat (house_id, rooms, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS flat_house_id_rooms_idx;
+DROP INDEX IF EXISTS flat_house_id_price_idx;
+-- +goose StatementEnd
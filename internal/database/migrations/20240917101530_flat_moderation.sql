@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS flat_moderation (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    flat_id INT NOT NULL,
+    moderator_id INT,
+    from_status VARCHAR(255) NOT NULL,
+    to_status VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_flat
+        FOREIGN KEY (flat_id)
+        REFERENCES flat(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS flat_moderation_flat_id_idx ON flat_moderation (flat_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS flat_moderation;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS role (
+    name VARCHAR(32) PRIMARY KEY NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS role_permission (
+    role VARCHAR(32) NOT NULL,
+    permission VARCHAR(64) NOT NULL,
+    PRIMARY KEY (role, permission),
+    CONSTRAINT fk_role
+        FOREIGN KEY (role)
+        REFERENCES role(name)
+        ON DELETE CASCADE
+);
+
+INSERT INTO role (name) VALUES ('client'), ('moderator'), ('admin') ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permission (role, permission)
+SELECT role, permission
+FROM (VALUES ('client'), ('moderator'), ('admin')) AS r(role)
+CROSS JOIN (VALUES ('house:read'), ('house:subscribe'), ('flat:create')) AS p(permission)
+UNION ALL
+SELECT role, permission
+FROM (VALUES ('moderator'), ('admin')) AS r(role)
+CROSS JOIN (VALUES ('house:create'), ('flat:moderate'), ('subscription:read'), ('data:import'), ('data:export')) AS p(permission)
+UNION ALL
+SELECT 'admin', 'user:manage'
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE "user" ALTER COLUMN user_type TYPE VARCHAR(32);
+ALTER TABLE "user" ADD CONSTRAINT fk_user_role FOREIGN KEY (user_type) REFERENCES role(name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- user_type keeps its width, users may already hold role names longer than VARCHAR(10)
+ALTER TABLE "user" DROP CONSTRAINT IF EXISTS fk_user_role;
+DROP TABLE IF EXISTS role_permission;
+DROP TABLE IF EXISTS role;
+-- +goose StatementEnd
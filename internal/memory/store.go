@@ -0,0 +1,84 @@
+// Package memory holds the shared state of the in-memory repositories. The
+// services keep their own repository types on top of Store, so a flat created
+// through flat.MemoryRepo is visible to house.MemoryRepo just like with PostgreSQL.
+package memory
+
+import (
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+type User struct {
+	ID           int
+	Email        string
+	PasswordHash string
+	UserType     string
+}
+
+type Flat struct {
+	usecase.FlatResponse
+	CreatedAt time.Time
+}
+
+// Store mirrors the database tables. Repositories must hold the embedded lock
+// while they access the maps, and return copies rather than pointers into them.
+type Store struct {
+	sync.RWMutex
+
+	Users         map[int]User
+	Houses        map[int]usecase.House
+	Flats         map[int]Flat
+	Subscribers   map[int]string // email by house_id, one subscriber per house
+	Notifications []usecase.Notification
+	Moderations   []usecase.Moderation
+	Roles         map[string][]usecase.Permission
+
+	sequences map[string]int
+}
+
+// NewStore returns an empty store with the roles seeded like the rbac migration does.
+func NewStore() *Store {
+	roles := make(map[string][]usecase.Permission, len(usecase.DefaultRoles))
+	for role, permissions := range usecase.DefaultRoles {
+		roles[role] = slices.Clone(permissions)
+	}
+
+	return &Store{
+		Users:       make(map[int]User),
+		Houses:      make(map[int]usecase.House),
+		Flats:       make(map[int]Flat),
+		Subscribers: make(map[int]string),
+		Roles:       roles,
+		sequences:   make(map[string]int),
+	}
+}
+
+// NextID returns the next identifier of table like a BIGSERIAL column.
+// The caller must hold the write lock.
+func (s *Store) NextID(table string) int {
+	s.sequences[table]++
+	return s.sequences[table]
+}
+
+// Atomic runs fn and restores the tables if it fails, like a rolled back
+// transaction. As in PostgreSQL the sequences are not restored. Changes made
+// concurrently by other callers are lost too, so Atomic is only fit for tests
+// that use the store from one goroutine at a time.
+func (s *Store) Atomic(fn func() error) error {
+	s.RLock()
+	users, houses, flats, subscribers := maps.Clone(s.Users), maps.Clone(s.Houses), maps.Clone(s.Flats), maps.Clone(s.Subscribers)
+	notifications, moderations := slices.Clone(s.Notifications), slices.Clone(s.Moderations)
+	s.RUnlock()
+
+	err := fn()
+	if err != nil {
+		s.Lock()
+		s.Users, s.Houses, s.Flats, s.Subscribers = users, houses, flats, subscribers
+		s.Notifications, s.Moderations = notifications, moderations
+		s.Unlock()
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"net/http"
+	"strconv"
+)
+
+const retryAfter = 5
+
+type Error struct {
+	Status    int          `json:"-"`
+	Message   string       `json:"message"`
+	RequestID string       `json:"request_id,omitempty"`
+	Code      Code         `json:"code"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	// Cause is the error behind an internal error. It is logged, never sent.
+	Cause error `json:"-"`
+}
+
+// FieldError describes a single invalid field of a request. In is where the field
+// was sent (body, query, path or header), Field is empty for the body as a whole.
+type FieldError struct {
+	Field   string `json:"field"`
+	In      string `json:"in,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+func (e Error) Unwrap() error {
+	return e.Cause
+}
+
+func New(status int, code Code, message string) Error {
+	return Error{Status: status, Code: code, Message: message}
+}
+
+func BadRequest(code Code, message string) Error {
+	return New(http.StatusBadRequest, code, message)
+}
+
+// Internal reports a failure of the service, cause is logged by Write.
+func Internal(message string, cause error) Error {
+	apiErr := New(http.StatusInternalServerError, CodeInternal, message)
+	apiErr.Cause = cause
+	return apiErr
+}
+
+// Mapping is the API representation of a sentinel error of a service package.
+type Mapping struct {
+	target error
+	apiErr Error
+}
+
+func Map(target error, status int, code Code, message string) Mapping {
+	return Mapping{target: target, apiErr: New(status, code, message)}
+}
+
+// Mapper resolves errors with the mappings of the services that may return
+// them. The zero Mapper only knows the errors of this package.
+type Mapper []Mapping
+
+// From resolves err to its API representation. A body cut by
+// http.MaxBytesReader is ErrTooLarge. Unknown errors are reported as internal
+// ones so that their details never leak to clients.
+func (m Mapper) From(err error) Error {
+	var apiErr Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return ErrTooLarge
+	}
+
+	for _, mapping := range m {
+		if errors.Is(err, mapping.target) {
+			return mapping.apiErr
+		}
+	}
+
+	return Internal("Internal server error", err)
+}
+
+func (m Mapper) Write(w http.ResponseWriter, r *http.Request, err error) {
+	apiErr := m.From(err)
+	apiErr.RequestID = logger.RequestID(r.Context())
+
+	if apiErr.Status >= http.StatusInternalServerError {
+		logger.FromContext(r.Context()).Error(apiErr.Message, "error", apiErr.Cause, "code", apiErr.Code)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if apiErr.Status >= http.StatusInternalServerError {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+
+	w.WriteHeader(apiErr.Status)
+	_ = json.NewEncoder(w).Encode(apiErr)
+}
+
+// From resolves err without service mappings, see Mapper.From.
+func From(err error) Error {
+	return Mapper(nil).From(err)
+}
+
+// Write writes err without service mappings, see Mapper.Write.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	Mapper(nil).Write(w, r, err)
+}
+
+var (
+	ErrInvalidPayload = BadRequest(CodeInvalidPayload, "Invalid request payload")
+	ErrMissingClaims  = New(http.StatusUnauthorized, CodeUnauthorized, "Invalid or missing user claims")
+	ErrForbidden      = New(http.StatusForbidden, CodeForbidden, "Forbidden")
+	ErrTooLarge       = New(http.StatusRequestEntityTooLarge, CodeRequestTooLarge, "Request body is too large")
+)
+
+func Validation(err error) Error {
+	return BadRequest(CodeValidation, err.Error())
+}
+
+// InvalidRequest reports a request that does not match the API specification.
+func InvalidRequest(fields []FieldError) Error {
+	apiErr := BadRequest(CodeValidation, "Request does not match the API specification")
+	apiErr.Fields = fields
+	return apiErr
+}
+
+func InvalidParameter(message string) Error {
+	return BadRequest(CodeInvalidParameter, message)
+}
@@ -0,0 +1,62 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMappedSentinel(t *testing.T) {
+	sentinel := errors.New("thing not found")
+	mapper := Mapper{Map(sentinel, http.StatusNotFound, CodeHouseNotFound, "Thing not found")}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(logger.WithRequestID(req.Context(), "req-1"))
+	w := httptest.NewRecorder()
+
+	mapper.Write(w, req, fmt.Errorf("lookup: %w", sentinel))
+
+	require.EqualValues(t, http.StatusNotFound, w.Code)
+	require.EqualValues(t, "application/json", w.Header().Get("Content-Type"))
+	require.Empty(t, w.Header().Get("Retry-After"))
+	require.JSONEq(t, `{"message": "Thing not found", "request_id": "req-1", "code": 10400}`, w.Body.String())
+}
+
+func TestUnmappedSentinelIsInternal(t *testing.T) {
+	sentinel := errors.New("thing not found")
+
+	apiErr := From(fmt.Errorf("lookup: %w", sentinel))
+	require.EqualValues(t, http.StatusInternalServerError, apiErr.Status)
+	require.ErrorIs(t, apiErr, sentinel, "the cause is kept for the log")
+}
+
+func TestWriteUnknownErrorIsInternal(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	Write(w, req, errors.New("pq: connection reset by peer"))
+
+	require.EqualValues(t, http.StatusInternalServerError, w.Code)
+	require.EqualValues(t, "5", w.Header().Get("Retry-After"))
+
+	var response Error
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.EqualValues(t, "Internal server error", response.Message)
+	require.EqualValues(t, CodeInternal, response.Code)
+}
+
+func TestWriteAPIError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	Write(w, req, InvalidParameter("id must be integer"))
+
+	require.EqualValues(t, http.StatusBadRequest, w.Code)
+	require.JSONEq(t, `{"message": "id must be integer", "code": 10102}`, w.Body.String())
+}
@@ -0,0 +1,37 @@
+package apierror
+
+type Code int
+
+// Codes are part of the public API contract: never renumber or reuse them.
+const (
+	CodeInternal Code = 10000
+
+	CodeInvalidPayload   Code = 10100
+	CodeValidation       Code = 10101
+	CodeInvalidParameter Code = 10102
+	CodeRequestTooLarge  Code = 10103
+
+	CodeAuthorizationMissing Code = 10200
+	CodeUnauthorized         Code = 10201
+	CodeTokenExpired         Code = 10202
+	CodeTokenNotValidYet     Code = 10203
+	CodeTokenInvalid         Code = 10204
+	CodeForbidden            Code = 10205
+
+	CodeUserNotFound    Code = 10300
+	CodeInvalidPassword Code = 10301
+	CodeUnknownRole     Code = 10302
+	CodeEmailTaken      Code = 10303
+
+	CodeHouseNotFound Code = 10400
+	CodeInvalidCursor Code = 10401
+
+	CodeFlatNotFound  Code = 10500
+	CodeDuplicateFlat Code = 10501
+
+	CodeQueryTooComplex Code = 10600
+
+	CodeInvalidImport    Code = 10700
+	CodeImportRolledBack Code = 10701
+	CodeImportAborted    Code = 10702
+)
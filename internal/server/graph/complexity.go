@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// defaultListSize is the number of items assumed for a list field without a
+// limit or ids argument.
+const defaultListSize = 20
+
+// complexity estimates how many fields the operation of p resolves: every field
+// costs 1 and the fields selected under a list are counted once per item.
+// Queries that fail to parse or validate cost nothing here, Exec reports them.
+func (h *Handler) complexity(p params) int {
+	document, errs := gqlparser.LoadQuery(h.ast, p.Query)
+	if len(errs) > 0 {
+		return 0
+	}
+
+	operation := document.Operations.ForName(p.OperationName)
+	if operation == nil {
+		return 0
+	}
+
+	return selectionCost(operation.SelectionSet, p.Variables)
+}
+
+func selectionCost(set ast.SelectionSet, variables map[string]any) int {
+	cost := 0
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			children := selectionCost(selection.SelectionSet, variables)
+			if selection.Definition != nil && selection.Definition.Type.Elem != nil {
+				children *= listSize(selection, variables)
+			}
+			cost += 1 + children
+		case *ast.InlineFragment:
+			cost += selectionCost(selection.SelectionSet, variables)
+		case *ast.FragmentSpread:
+			if selection.Definition != nil {
+				cost += selectionCost(selection.Definition.SelectionSet, variables)
+			}
+		}
+	}
+	return cost
+}
+
+func listSize(field *ast.Field, variables map[string]any) int {
+	if arg := field.Arguments.ForName("limit"); arg != nil {
+		value, _ := arg.Value.Value(variables)
+		switch limit := value.(type) {
+		case int64:
+			return max(int(limit), 0)
+		case float64:
+			return max(int(limit), 0)
+		}
+	}
+
+	if arg := field.Arguments.ForName("ids"); arg != nil {
+		value, _ := arg.Value.Value(variables)
+		if ids, ok := value.([]any); ok {
+			return len(ids)
+		}
+	}
+
+	return defaultListSize
+}
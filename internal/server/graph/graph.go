@@ -0,0 +1,146 @@
+// Package graph serves the read-only GraphQL API described in schema.graphql on
+// top of the batch readers of the service repositories.
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"net/http"
+)
+
+const maxParallelism = 10
+
+//go:embed schema.graphql
+var schema string
+
+type Config struct {
+	// MaxDepth is the deepest field nesting a query may have.
+	MaxDepth int `yaml:"max_depth" validate:"gt=0"`
+	// MaxComplexity is the most fields a query may resolve, see complexity.
+	MaxComplexity int `yaml:"max_complexity" validate:"gt=0"`
+}
+
+type Handler struct {
+	config  Config
+	schema  *graphql.Schema
+	ast     *ast.Schema
+	errors  apierror.Mapper
+	readers readers
+}
+
+type readers struct {
+	houses        house.BatchReader
+	flats         flat.BatchReader
+	subscriptions sender.BatchReader
+	users         auth.BatchReader
+}
+
+// NewHandler builds the GraphQL handler. errors resolves the errors returned by
+// the readers, see resolveErrors.
+func NewHandler(config Config, errors apierror.Mapper, houses house.BatchReader, flats flat.BatchReader, subscriptions sender.BatchReader, users auth.BatchReader) (*Handler, error) {
+	parsed, err := graphql.ParseSchema(schema, &resolver{},
+		graphql.MaxDepth(config.MaxDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+
+	loaded, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: schema})
+	if err != nil {
+		return nil, fmt.Errorf("load schema: %w", err)
+	}
+
+	return &Handler{
+		config:  config,
+		schema:  parsed,
+		ast:     loaded,
+		errors:  errors,
+		readers: readers{houses: houses, flats: flats, subscriptions: subscriptions, users: users},
+	}, nil
+}
+
+type params struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// ServeHTTP executes a query sent as JSON in the request body. Errors of the
+// query itself are reported in the GraphQL response with the API error code in
+// their extensions, only malformed requests get an API error.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.Claims)
+	if !ok {
+		apierror.Write(w, r, apierror.ErrMissingClaims)
+		return
+	}
+
+	var p params
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil || p.Query == "" {
+		apierror.Write(w, r, apierror.ErrInvalidPayload)
+		return
+	}
+
+	var response *graphql.Response
+	if cost := h.complexity(p); cost > h.config.MaxComplexity {
+		response = &graphql.Response{Errors: []*gqlerrors.QueryError{{
+			Message:    fmt.Sprintf("Query has complexity %d that exceeds max complexity %d", cost, h.config.MaxComplexity),
+			Extensions: map[string]any{"code": apierror.CodeQueryTooComplex},
+		}}}
+	} else {
+		ctx := withRequest(r.Context(), claims, newLoaders(h.readers, !claims.Can(usecase.PermFlatModerate)))
+		response = h.schema.Exec(ctx, p.Query, p.OperationName, p.Variables)
+		h.resolveErrors(r.Context(), response.Errors)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// resolveErrors replaces the errors returned by resolvers with their API
+// representation, the same way apierror.Mapper.Write does for HTTP responses,
+// so that internal details never leak to clients.
+func (h *Handler) resolveErrors(ctx context.Context, errs []*gqlerrors.QueryError) {
+	for _, err := range errs {
+		switch {
+		case err.ResolverError != nil:
+			apiErr := h.errors.From(err.ResolverError)
+			if apiErr.Status >= http.StatusInternalServerError {
+				logger.FromContext(ctx).Error(apiErr.Message, "error", err.ResolverError, "code", apiErr.Code)
+			}
+			err.Message = apiErr.Message
+			err.Extensions = map[string]any{"code": apiErr.Code}
+		case err.Rule == "MaxDepthExceeded":
+			err.Extensions = map[string]any{"code": apierror.CodeQueryTooComplex}
+		}
+	}
+}
+
+type requestKey struct{}
+
+type request struct {
+	claims  *auth.Claims
+	loaders *loaders
+}
+
+func withRequest(ctx context.Context, claims *auth.Claims, loaders *loaders) context.Context {
+	return context.WithValue(ctx, requestKey{}, &request{claims: claims, loaders: loaders})
+}
+
+func fromContext(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
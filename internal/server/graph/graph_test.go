@@ -0,0 +1,223 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingFlats counts the FlatsByHouse calls to check that they are batched.
+type countingFlats struct {
+	*flat.MemoryRepo
+	calls atomic.Int32
+}
+
+func (repo *countingFlats) FlatsByHouse(ctx context.Context, houseIDs []int, approvedOnly bool, limit int) ([]usecase.FlatResponse, error) {
+	repo.calls.Add(1)
+	return repo.MemoryRepo.FlatsByHouse(ctx, houseIDs, approvedOnly, limit)
+}
+
+type fixture struct {
+	handler   *Handler
+	flats     *countingFlats
+	users     *auth.MemoryRepo
+	moderator int
+	houses    []int
+}
+
+// newFixture creates two houses with an approved and a created flat each. The
+// approved flats were moderated by the registered moderator.
+func newFixture(t *testing.T, config Config) *fixture {
+	ctx := context.Background()
+	store := memory.NewStore()
+	tokens := auth.NewTokenManager(auth.Config{Secret: "test-secret", TokenTTL: time.Hour, Issuer: "test"})
+	users := auth.NewMemoryRepo(store, tokens)
+	houses := house.NewMemoryRepo(store)
+	flats := &countingFlats{MemoryRepo: flat.NewMemoryRepo(store)}
+	subscriptions := sender.NewMemoryRepo(store)
+
+	handler, err := NewHandler(config, slices.Concat(auth.Errors, house.Errors, flat.Errors), houses, flats, subscriptions, users)
+	require.NoError(t, err)
+
+	user, err := users.Register(ctx, usecase.CreateUserRequest{Email: "moderator@example.com", Password: "password", UserType: usecase.RoleModerator})
+	require.NoError(t, err)
+	f := &fixture{handler: handler, flats: flats, users: users, moderator: user.UserId}
+
+	for _, address := range []string{"Лесная улица, 7, Москва", "Невский проспект, 1, Санкт-Петербург"} {
+		created, err := houses.Create(ctx, usecase.HouseCreateRequest{Address: address, Year: 2000})
+		require.NoError(t, err)
+		f.houses = append(f.houses, created.ID)
+
+		require.NoError(t, subscriptions.Subscribe(ctx, created.ID, usecase.Subscribe{Email: "user@example.com"}))
+
+		for number := 1; number <= 2; number++ {
+			fl, err := flats.Create(ctx, usecase.FlatCreateRequest{Number: number, HouseID: created.ID, Price: 1000, Rooms: 2})
+			require.NoError(t, err)
+			if number == 1 {
+				_, err = flats.Update(ctx, usecase.FlatUpdateRequest{ID: fl.ID, Status: approved, ModeratorID: f.moderator})
+				require.NoError(t, err)
+			}
+		}
+	}
+
+	return f
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (f *fixture) query(t *testing.T, role, query string, variables map[string]any) response {
+	body, err := json.Marshal(params{Query: query, Variables: variables})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	r = r.WithContext(context.WithValue(r.Context(), "claims", &auth.Claims{UserID: f.moderator, Role: role, Permissions: usecase.DefaultRoles[role]}))
+	w := httptest.NewRecorder()
+	f.handler.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func config() Config {
+	return Config{MaxDepth: 8, MaxComplexity: 1000}
+}
+
+func TestRoleAwareFlats(t *testing.T) {
+	f := newFixture(t, config())
+	query := `query($id: ID!) { house(id: $id) { flats { number status } } }`
+	variables := map[string]any{"id": strconv.Itoa(f.houses[0])}
+
+	resp := f.query(t, usecase.RoleClient, query, variables)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"house": {"flats": [{"number": 1, "status": "approved"}]}}`, string(resp.Data))
+
+	resp = f.query(t, usecase.RoleModerator, query, variables)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"house": {"flats": [{"number": 1, "status": "approved"}, {"number": 2, "status": "created"}]}}`, string(resp.Data))
+
+	// The created flat of the first house is hidden from clients when asked for directly too
+	resp = f.query(t, usecase.RoleClient, `{ flat(id: 2) { id } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"flat": null}`, string(resp.Data))
+}
+
+func TestModeratorOnlyFields(t *testing.T) {
+	f := newFixture(t, config())
+	query := `{ flat(id: 1) { house { subscriptions { email } } moderationHistory { fromStatus toStatus moderator { email userType } } } }`
+
+	resp := f.query(t, usecase.RoleModerator, query, nil)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"flat": {
+		"house": {"subscriptions": [{"email": "user@example.com"}]},
+		"moderationHistory": [{"fromStatus": "created", "toStatus": "approved", "moderator": {"email": "moderator@example.com", "userType": "moderator"}}]
+	}}`, string(resp.Data))
+
+	resp = f.query(t, usecase.RoleClient, query, nil)
+	require.Len(t, resp.Errors, 2)
+	for _, err := range resp.Errors {
+		require.Equal(t, apierror.ErrForbidden.Message, err.Message)
+		require.EqualValues(t, apierror.CodeForbidden, err.Extensions["code"])
+	}
+}
+
+func TestAdminUserType(t *testing.T) {
+	f := newFixture(t, config())
+	require.NoError(t, f.users.SetUserType(context.Background(), f.moderator, usecase.RoleAdmin))
+
+	resp := f.query(t, usecase.RoleAdmin, `{ me { userType } flat(id: 1) { moderationHistory { moderator { userType } } } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{
+		"me": {"userType": "admin"},
+		"flat": {"moderationHistory": [{"moderator": {"userType": "admin"}}]}
+	}`, string(resp.Data))
+}
+
+func TestFlatsAreBatched(t *testing.T) {
+	f := newFixture(t, config())
+
+	resp := f.query(t, usecase.RoleModerator, `query($ids: [ID!]!) { houses(ids: $ids) { id flats { number house { id } } } }`, map[string]any{
+		"ids": []string{strconv.Itoa(f.houses[0]), strconv.Itoa(f.houses[1]), "404"},
+	})
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		Houses []struct {
+			Flats []any `json:"flats"`
+		} `json:"houses"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	require.Len(t, data.Houses, 2)
+	for _, h := range data.Houses {
+		require.Len(t, h.Flats, 2)
+	}
+	require.EqualValues(t, 1, f.flats.calls.Load())
+}
+
+func TestLimits(t *testing.T) {
+	f := newFixture(t, Config{MaxDepth: 4, MaxComplexity: 50})
+
+	resp := f.query(t, usecase.RoleModerator, `{ house(id: 1) { flats(limit: 1) { house { flats(limit: 1) { house { id } } } } } }`, nil)
+	require.Nil(t, resp.Data)
+	require.NotEmpty(t, resp.Errors)
+	require.Contains(t, resp.Errors[0].Message, "exceeds max depth 4")
+	require.EqualValues(t, apierror.CodeQueryTooComplex, resp.Errors[0].Extensions["code"])
+
+	// The house costs 2 and every flat 4: 82 with the default list size, 6 with a limit
+	query := `query($limit: Int) { house(id: 1) { flats(limit: $limit) { id number house { id } } } }`
+	resp = f.query(t, usecase.RoleModerator, query, nil)
+	require.Nil(t, resp.Data)
+	require.Len(t, resp.Errors, 1)
+	require.EqualValues(t, apierror.CodeQueryTooComplex, resp.Errors[0].Extensions["code"])
+
+	resp = f.query(t, usecase.RoleModerator, query, map[string]any{"limit": 1})
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"house": {"flats": [{"id": "1", "number": 1, "house": {"id": "1"}}]}}`, string(resp.Data))
+}
+
+func TestFlatsDefaultLimit(t *testing.T) {
+	f := newFixture(t, Config{MaxDepth: 8, MaxComplexity: 1000})
+	for number := 3; number <= defaultListSize+5; number++ {
+		_, err := f.flats.Create(context.Background(), usecase.FlatCreateRequest{Number: number, HouseID: f.houses[0], Price: 1000, Rooms: 2})
+		require.NoError(t, err)
+	}
+
+	query := `query($limit: Int) { house(id: 1) { flats(limit: $limit) { number } } }`
+	var data struct {
+		House struct {
+			Flats []struct{ Number int }
+		}
+	}
+	for limit, want := range map[any]int{nil: defaultListSize, 3: 3, 0: 0, 100: defaultListSize + 5} {
+		resp := f.query(t, usecase.RoleModerator, query, map[string]any{"limit": limit})
+		require.Empty(t, resp.Errors)
+		require.NoError(t, json.Unmarshal(resp.Data, &data))
+		require.Len(t, data.House.Flats, want, "limit %v", limit)
+		if want > 0 {
+			require.Equal(t, 1, data.House.Flats[0].Number)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package graph
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/graph-gophers/dataloader"
+	"strconv"
+	"sync"
+)
+
+// loaders batch the lookups of one request: every resolver asking for a key
+// while a batch is collected is served by a single repository call.
+type loaders struct {
+	houses        *dataloader.Loader
+	flats         *dataloader.Loader
+	moderations   *dataloader.Loader
+	subscriptions *dataloader.Loader
+	users         *dataloader.Loader
+
+	// houseFlats has a loader per limit, the limit is applied by the database
+	houseFlats    map[int]*dataloader.Loader
+	newHouseFlats func(limit int) *dataloader.Loader
+	mu            sync.Mutex
+}
+
+// newLoaders builds the loaders of a request. approvedOnly hides the flats that
+// are not approved from the flats of a house, like ClientFlats does.
+func newLoaders(readers readers, approvedOnly bool) *loaders {
+	newHouseFlats := func(limit int) *dataloader.Loader {
+		houseFlats := func(ctx context.Context, houseIDs []int) ([]usecase.FlatResponse, error) {
+			return readers.flats.FlatsByHouse(ctx, houseIDs, approvedOnly, limit)
+		}
+		return dataloader.NewBatchedLoader(batch(houseFlats, func(flat usecase.FlatResponse) int {
+			return flat.HouseID
+		}))
+	}
+
+	return &loaders{
+		houses: dataloader.NewBatchedLoader(batch(readers.houses.HousesByID, func(house usecase.House) int {
+			return house.ID
+		})),
+		flats: dataloader.NewBatchedLoader(batch(readers.flats.FlatsByID, func(flat usecase.FlatResponse) int {
+			return flat.ID
+		})),
+		moderations: dataloader.NewBatchedLoader(batch(readers.flats.ModerationsByFlat, func(moderation usecase.Moderation) int {
+			return moderation.FlatID
+		})),
+		subscriptions: dataloader.NewBatchedLoader(batch(readers.subscriptions.SubscriptionsByHouse, func(subscription usecase.Subscription) int {
+			return subscription.HouseID
+		})),
+		users: dataloader.NewBatchedLoader(batch(readers.users.UsersByID, func(user usecase.User) int {
+			return user.ID
+		})),
+		houseFlats:    make(map[int]*dataloader.Loader),
+		newHouseFlats: newHouseFlats,
+	}
+}
+
+// flatsOfHouse returns the loader of the first limit flats of houses.
+func (l *loaders) flatsOfHouse(limit int) *dataloader.Loader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	loader, ok := l.houseFlats[limit]
+	if !ok {
+		loader = l.newHouseFlats(limit)
+		l.houseFlats[limit] = loader
+	}
+	return loader
+}
+
+type key int
+
+func (k key) String() string {
+	return strconv.Itoa(int(k))
+}
+
+func (k key) Raw() any {
+	return int(k)
+}
+
+// batch turns a repository call returning the rows of many ids into a batch
+// function, group tells which id a row belongs to.
+func batch[T any](load func(ctx context.Context, ids []int) ([]T, error), group func(T) int) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		ids := make([]int, len(keys))
+		for i, k := range keys {
+			ids[i] = k.Raw().(int)
+		}
+
+		results := make([]*dataloader.Result, len(keys))
+		rows, err := load(ctx, ids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result{Error: err}
+			}
+			return results
+		}
+
+		grouped := make(map[int][]T, len(ids))
+		for _, row := range rows {
+			grouped[group(row)] = append(grouped[group(row)], row)
+		}
+		for i, id := range ids {
+			results[i] = &dataloader.Result{Data: grouped[id]}
+		}
+		return results
+	}
+}
+
+// loadAll returns the rows of id.
+func loadAll[T any](ctx context.Context, loader *dataloader.Loader, id int) ([]T, error) {
+	data, err := loader.Load(ctx, key(id))()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _ := data.([]T)
+	return rows, nil
+}
+
+// loadOne returns the row of id, or nil if there is none.
+func loadOne[T any](ctx context.Context, loader *dataloader.Loader, id int) (*T, error) {
+	rows, err := loadAll[T](ctx, loader, id)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	return &rows[0], nil
+}
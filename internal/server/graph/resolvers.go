@@ -0,0 +1,274 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/graph-gophers/dataloader"
+	"github.com/graph-gophers/graphql-go"
+	"strconv"
+)
+
+const approved = "approved"
+
+var errInvalidID = apierror.InvalidParameter("Invalid id")
+
+// resolver resolves Query. The claims and loaders of the request are taken from
+// the context, so one resolver serves every request.
+type resolver struct{}
+
+func (resolver) House(ctx context.Context, args struct{ ID graphql.ID }) (*houseResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadHouse(ctx, id)
+}
+
+func (resolver) Houses(ctx context.Context, args struct{ IDs []graphql.ID }) ([]*houseResolver, error) {
+	keys := make(dataloader.Keys, len(args.IDs))
+	for i, arg := range args.IDs {
+		id, err := parseID(arg)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key(id)
+	}
+
+	data, errs := fromContext(ctx).loaders.houses.LoadMany(ctx, keys)()
+	houses := make([]*houseResolver, 0, len(data))
+	for i := range data {
+		if errs != nil && errs[i] != nil {
+			return nil, errs[i]
+		}
+		if rows, _ := data[i].([]usecase.House); len(rows) > 0 {
+			houses = append(houses, &houseResolver{house: rows[0]})
+		}
+	}
+	return houses, nil
+}
+
+func (resolver) Flat(ctx context.Context, args struct{ ID graphql.ID }) (*flatResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := fromContext(ctx)
+	flat, err := loadOne[usecase.FlatResponse](ctx, request.loaders.flats, id)
+	if err != nil || flat == nil {
+		return nil, err
+	}
+	if flat.Status != approved && !request.claims.Can(usecase.PermFlatModerate) {
+		return nil, nil
+	}
+
+	return &flatResolver{flat: *flat}, nil
+}
+
+func (resolver) Me(ctx context.Context) (*userResolver, error) {
+	request := fromContext(ctx)
+	return loadUser(ctx, request.claims.UserID)
+}
+
+type houseResolver struct {
+	house usecase.House
+}
+
+func (r *houseResolver) ID() graphql.ID {
+	return toID(r.house.ID)
+}
+
+func (r *houseResolver) Address() string {
+	return r.house.Address
+}
+
+func (r *houseResolver) Year() int32 {
+	return int32(r.house.Year)
+}
+
+func (r *houseResolver) Developer() *string {
+	if r.house.Developer == "" {
+		return nil
+	}
+	return &r.house.Developer
+}
+
+func (r *houseResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.house.CreatedAt}
+}
+
+func (r *houseResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.house.UpdatedAt}
+}
+
+func (r *houseResolver) Flats(ctx context.Context, args struct{ Limit *int32 }) ([]*flatResolver, error) {
+	if args.Limit != nil && *args.Limit < 0 {
+		return nil, apierror.InvalidParameter("Limit must not be negative")
+	}
+
+	// Without a limit as many flats are returned as complexity assumes
+	limit := defaultListSize
+	if args.Limit != nil {
+		limit = int(*args.Limit)
+	}
+	if limit == 0 {
+		return []*flatResolver{}, nil
+	}
+
+	flats, err := loadAll[usecase.FlatResponse](ctx, fromContext(ctx).loaders.flatsOfHouse(limit), r.house.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*flatResolver, len(flats))
+	for i, flat := range flats {
+		resolvers[i] = &flatResolver{flat: flat}
+	}
+	return resolvers, nil
+}
+
+func (r *houseResolver) Subscriptions(ctx context.Context) ([]*subscriptionResolver, error) {
+	request := fromContext(ctx)
+	if !request.claims.Can(usecase.PermSubscriptionRead) {
+		return nil, apierror.ErrForbidden
+	}
+
+	subscriptions, err := loadAll[usecase.Subscription](ctx, request.loaders.subscriptions, r.house.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*subscriptionResolver, len(subscriptions))
+	for i, subscription := range subscriptions {
+		resolvers[i] = &subscriptionResolver{subscription: subscription}
+	}
+	return resolvers, nil
+}
+
+type flatResolver struct {
+	flat usecase.FlatResponse
+}
+
+func (r *flatResolver) ID() graphql.ID {
+	return toID(r.flat.ID)
+}
+
+func (r *flatResolver) Number() int32 {
+	return int32(r.flat.Number)
+}
+
+func (r *flatResolver) Price() int32 {
+	return int32(r.flat.Price)
+}
+
+func (r *flatResolver) Rooms() int32 {
+	return int32(r.flat.Rooms)
+}
+
+func (r *flatResolver) Status() string {
+	return r.flat.Status
+}
+
+func (r *flatResolver) House(ctx context.Context) (*houseResolver, error) {
+	house, err := loadHouse(ctx, r.flat.HouseID)
+	if err == nil && house == nil {
+		return nil, apierror.Internal("Flat without a house", fmt.Errorf("house %d of flat %d not found", r.flat.HouseID, r.flat.ID))
+	}
+	return house, err
+}
+
+func (r *flatResolver) ModerationHistory(ctx context.Context) ([]*moderationResolver, error) {
+	request := fromContext(ctx)
+	if !request.claims.Can(usecase.PermFlatModerate) {
+		return nil, apierror.ErrForbidden
+	}
+
+	moderations, err := loadAll[usecase.Moderation](ctx, request.loaders.moderations, r.flat.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*moderationResolver, len(moderations))
+	for i, moderation := range moderations {
+		resolvers[i] = &moderationResolver{moderation: moderation}
+	}
+	return resolvers, nil
+}
+
+type moderationResolver struct {
+	moderation usecase.Moderation
+}
+
+func (r *moderationResolver) FromStatus() string {
+	return r.moderation.FromStatus
+}
+
+func (r *moderationResolver) ToStatus() string {
+	return r.moderation.ToStatus
+}
+
+func (r *moderationResolver) Moderator(ctx context.Context) (*userResolver, error) {
+	if r.moderation.ModeratorID == 0 {
+		return nil, nil
+	}
+	return loadUser(ctx, r.moderation.ModeratorID)
+}
+
+func (r *moderationResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.moderation.CreatedAt}
+}
+
+type subscriptionResolver struct {
+	subscription usecase.Subscription
+}
+
+func (r *subscriptionResolver) Email() string {
+	return r.subscription.Email
+}
+
+type userResolver struct {
+	user usecase.User
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return toID(r.user.ID)
+}
+
+func (r *userResolver) Email() string {
+	return r.user.Email
+}
+
+func (r *userResolver) UserType() string {
+	return r.user.UserType
+}
+
+func loadHouse(ctx context.Context, id int) (*houseResolver, error) {
+	house, err := loadOne[usecase.House](ctx, fromContext(ctx).loaders.houses, id)
+	if err != nil || house == nil {
+		return nil, err
+	}
+	return &houseResolver{house: *house}, nil
+}
+
+func loadUser(ctx context.Context, id int) (*userResolver, error) {
+	user, err := loadOne[usecase.User](ctx, fromContext(ctx).loaders.users, id)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return &userResolver{user: *user}, nil
+}
+
+func parseID(id graphql.ID) (int, error) {
+	value, err := strconv.Atoi(string(id))
+	if err != nil || value <= 0 {
+		return 0, errInvalidID
+	}
+	return value, nil
+}
+
+func toID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
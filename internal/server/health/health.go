@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	statusOK       = "ok"
+	statusFailing  = "failing"
+	statusDraining = "draining"
+
+	checkTimeout = 2 * time.Second
+)
+
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type Response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type Handler struct {
+	checks   []Check
+	draining atomic.Bool
+}
+
+func NewHandler(checks ...Check) *Handler {
+	return &Handler{checks: checks}
+}
+
+// Drain makes the readiness probe fail so that load balancers stop routing
+// new traffic before the server shuts down.
+func (h *Handler) Drain() {
+	h.draining.Store(true)
+}
+
+func (h *Handler) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, http.StatusOK, Response{Status: statusOK})
+}
+
+func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		writeResponse(w, http.StatusServiceUnavailable, Response{Status: statusDraining})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	response := Response{Status: statusOK, Checks: make(map[string]string, len(h.checks))}
+	for _, check := range h.checks {
+		if err := check.Check(ctx); err != nil {
+			response.Status = statusFailing
+			response.Checks[check.Name] = err.Error()
+			continue
+		}
+		response.Checks[check.Name] = statusOK
+	}
+
+	status := http.StatusOK
+	if response.Status != statusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeResponse(w, status, response)
+}
+
+func writeResponse(w http.ResponseWriter, status int, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
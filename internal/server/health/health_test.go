@@ -0,0 +1,56 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLiveness(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewHandler().Liveness(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	require.EqualValues(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+}
+
+func TestReadiness(t *testing.T) {
+	var dbErr error
+	handler := NewHandler(
+		Check{Name: "database", Check: func(context.Context) error { return dbErr }},
+		Check{Name: "sender", Check: func(context.Context) error { return nil }},
+	)
+
+	w := httptest.NewRecorder()
+	handler.Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.EqualValues(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"status": "ok", "checks": {"database": "ok", "sender": "ok"}}`, w.Body.String())
+
+	dbErr = errors.New("connection refused")
+	w = httptest.NewRecorder()
+	handler.Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.EqualValues(t, http.StatusServiceUnavailable, w.Code)
+
+	var response Response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.EqualValues(t, "failing", response.Status)
+	require.EqualValues(t, "connection refused", response.Checks["database"])
+}
+
+func TestReadinessDraining(t *testing.T) {
+	handler := NewHandler(Check{Name: "database", Check: func(context.Context) error { return nil }})
+	handler.Drain()
+
+	w := httptest.NewRecorder()
+	handler.Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.EqualValues(t, http.StatusServiceUnavailable, w.Code)
+	require.JSONEq(t, `{"status": "draining"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.Liveness(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.EqualValues(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"net/http"
+)
+
+// MaxBodySize rejects requests whose body is larger than limit bytes with a
+// 413. A declared Content-Length over the limit is rejected before the body is
+// read; otherwise reading past the limit fails with http.MaxBytesError, which
+// apierror resolves to the same 413.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				apierror.Write(w, r, apierror.ErrTooLarge)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
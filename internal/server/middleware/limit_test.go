@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"encoding/json"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	spec, err := api.Load()
+	require.NoError(t, err)
+	validate, err := RequestValidator(spec)
+	require.NoError(t, err)
+
+	var reached string
+	router := chi.NewRouter()
+	router.With(MaxBodySize(16), validate).Post("/v2/import", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		reached = string(body)
+	})
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		status        int
+	}{
+		{name: "within the limit", body: "kind\nhouse\n", status: http.StatusOK},
+		{name: "declared over the limit", body: strings.Repeat("house\n", 10), status: http.StatusRequestEntityTooLarge},
+		{name: "streamed over the limit", body: strings.Repeat("house\n", 10), contentLength: -1, status: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached = ""
+			req := httptest.NewRequest(http.MethodPost, "/v2/import?format=csv", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/octet-stream")
+			if tt.contentLength != 0 {
+				req.ContentLength = tt.contentLength
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.EqualValues(t, tt.status, w.Code, w.Body.String())
+			if tt.status == http.StatusOK {
+				require.Equal(t, tt.body, reached)
+				return
+			}
+			require.Empty(t, reached)
+			var response apierror.Error
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			require.EqualValues(t, apierror.CodeRequestTooLarge, response.Code)
+		})
+	}
+}
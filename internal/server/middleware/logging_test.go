@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDPropagated(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.EqualValues(t, "abc-123", seen)
+	require.EqualValues(t, "abc-123", w.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIDAssigned(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "bad id with spaces")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Len(t, seen, 32)
+	require.EqualValues(t, seen, w.Header().Get("X-Request-ID"))
+}
+
+func TestLoggerAccessEntry(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(logger.New(&buf, "info"))
+	defer slog.SetDefault(defaultLogger)
+
+	router := chi.NewRouter()
+	router.Use(RequestID, Logger)
+	router.Get("/house/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ctx := logger.With(r.Context(), "user_id", 42, "role", "moderator")
+		logger.FromContext(ctx).Info("inside handler")
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/house/7", nil)
+	req.Header.Set("X-Request-ID", "req-7")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var inner, access map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &inner))
+	require.NoError(t, json.Unmarshal(lines[1], &access))
+
+	require.EqualValues(t, "req-7", inner["request_id"])
+	require.EqualValues(t, "req-7", access["request_id"])
+	require.EqualValues(t, 42, access["user_id"])
+	require.EqualValues(t, "moderator", access["role"])
+	require.EqualValues(t, "/house/{id}", access["route"])
+	require.EqualValues(t, http.StatusTeapot, access["status"])
+	require.Contains(t, access, "latency")
+}
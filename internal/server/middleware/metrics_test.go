@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsLabelledByRoutePattern(t *testing.T) {
+	router := chi.NewRouter()
+	router.Use(Metrics)
+	router.Get("/house/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	counter := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/house/{id}", "404")
+	before := testutil.ToFloat64(counter)
+
+	for _, path := range []string{"/house/1", "/house/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.EqualValues(t, before+2, testutil.ToFloat64(counter))
+}
+
+func TestMetricsUnmatchedRoute(t *testing.T) {
+	router := chi.NewRouter()
+	router.Use(Metrics)
+	router.Get("/house/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	counter := metrics.HTTPRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")
+	before := testutil.ToFloat64(counter)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/no/such/path", nil))
+
+	require.EqualValues(t, before+1, testutil.ToFloat64(counter))
+}
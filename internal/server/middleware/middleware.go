@@ -2,65 +2,58 @@ package middleware
 
 import (
 	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
 	"net/http"
 	"strings"
 )
 
-const (
-	client    = "client"
-	moderator = "moderator"
-	claimsKey = "claims"
-)
+const claimsKey = "claims"
 
-func TokenAuthenticator(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header missing", http.StatusUnauthorized)
-			return
-		}
+var errAuthorizationMissing = apierror.New(http.StatusUnauthorized, apierror.CodeAuthorizationMissing, "Authorization header missing")
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+type TokenParser interface {
+	Parse(tokenString string) (*auth.Claims, error)
+}
 
-		claims, err := auth.ParseToken(tokenString)
-		if err != nil {
-			switch err {
-			case auth.ErrTokenExpired:
-				http.Error(w, "Token expired", http.StatusUnauthorized)
-			case auth.ErrTokenNotValidYet:
-				http.Error(w, "Token not valid yet", http.StatusUnauthorized)
-			case auth.ErrTokenInvalid:
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
-			default:
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+// TokenAuthenticator verifies the bearer token with parser and stores its claims in the request context.
+func TokenAuthenticator(parser TokenParser) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				apierror.Write(w, r, errAuthorizationMissing)
+				return
 			}
-			return
-		}
 
-		ctx := context.WithValue(r.Context(), claimsKey, claims)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
-}
+			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-func AuthOnly(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		claims := r.Context().Value(claimsKey).(*auth.Claims)
-		if claims.Role != client && claims.Role != moderator {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
+			claims, err := parser.Parse(tokenString)
+			if err != nil {
+				auth.Errors.Write(w, r, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), claimsKey, claims)
+			ctx = logger.With(ctx, "user_id", claims.UserID, "role", claims.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
-func ModerationOnly(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		claims := r.Context().Value(claimsKey).(*auth.Claims)
-		if claims.Role != moderator {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
+// RequirePermission lets the request through only if the claims stored by
+// TokenAuthenticator grant permission.
+func RequirePermission(permission usecase.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(claimsKey).(*auth.Claims)
+			if !ok || !claims.Can(permission) {
+				apierror.Write(w, r, apierror.ErrForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
 }
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"net/http"
+	"strings"
+)
+
+const inBody = "body"
+
+// RequestValidator rejects requests that do not match spec with a 400 listing
+// every invalid parameter and body field. Requests to paths the spec does not
+// describe are passed through. Security requirements are left to TokenAuthenticator,
+// so it should run after it.
+func RequestValidator(spec *openapi3.T) (func(http.Handler) http.Handler, error) {
+	specRouter, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := specRouter.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// ValidateRequest puts the body back after reading it, handlers decode it again
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			})
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				apierror.Write(w, r, apierror.ErrTooLarge)
+				return
+			}
+			if err != nil {
+				apierror.Write(w, r, apierror.InvalidRequest(fieldErrors(err)))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// fieldErrors flattens the errors of openapi3filter.ValidateRequest into one
+// entry per invalid field.
+func fieldErrors(err error) []apierror.FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var fields []apierror.FieldError
+		for _, inner := range e {
+			fields = append(fields, fieldErrors(inner)...)
+		}
+		return fields
+	case *openapi3filter.RequestError:
+		if e.Parameter != nil {
+			return []apierror.FieldError{{Field: e.Parameter.Name, In: e.Parameter.In, Message: reason(e)}}
+		}
+		if fields := schemaErrors(e.Err); len(fields) > 0 {
+			return fields
+		}
+		return []apierror.FieldError{{In: inBody, Message: reason(e)}}
+	default:
+		return []apierror.FieldError{{Message: err.Error()}}
+	}
+}
+
+// schemaErrors returns the schema violations in err by the dotted path of the
+// offending body field.
+func schemaErrors(err error) []apierror.FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var fields []apierror.FieldError
+		for _, inner := range e {
+			fields = append(fields, schemaErrors(inner)...)
+		}
+		return fields
+	case *openapi3.SchemaError:
+		return []apierror.FieldError{{Field: strings.Join(e.JSONPointer(), "."), In: inBody, Message: e.Reason}}
+	default:
+		return nil
+	}
+}
+
+func reason(err *openapi3filter.RequestError) string {
+	var messages []string
+	for _, field := range schemaErrors(err.Err) {
+		messages = append(messages, field.Message)
+	}
+	if len(messages) > 0 {
+		return strings.Join(messages, "; ")
+	}
+
+	if err.Err != nil {
+		return err.Err.Error()
+	}
+	return err.Reason
+}
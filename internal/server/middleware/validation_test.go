@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"encoding/json"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newValidatedRouter(t *testing.T, reached *string) *chi.Mux {
+	spec, err := api.Load()
+	require.NoError(t, err)
+	validate, err := RequestValidator(spec)
+	require.NoError(t, err)
+
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		*reached = string(body)
+	}
+
+	router := chi.NewRouter()
+	router.Use(validate)
+	router.Post("/v2/flat/create", echo)
+	router.Get("/v2/house/{id}", echo)
+	router.Get("/v2/undocumented", echo)
+	return router
+}
+
+func TestRequestValidatorListsEveryInvalidField(t *testing.T) {
+	var reached string
+	router := newValidatedRouter(t, &reached)
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/flat/create", strings.NewReader(`{"number": 0, "house_id": "one", "price": 100}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.EqualValues(t, http.StatusBadRequest, w.Code)
+	require.Empty(t, reached)
+
+	var response apierror.Error
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.EqualValues(t, apierror.CodeValidation, response.Code)
+
+	fields := make(map[string]string)
+	for _, field := range response.Fields {
+		require.EqualValues(t, "body", field.In)
+		require.NotEmpty(t, field.Message)
+		fields[field.Field] = field.Message
+	}
+	require.Len(t, fields, 3)
+	require.Contains(t, fields, "number")
+	require.Contains(t, fields, "house_id")
+	require.Contains(t, fields, "rooms")
+}
+
+func TestRequestValidatorPathParameter(t *testing.T) {
+	var reached string
+	router := newValidatedRouter(t, &reached)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/house/abc?limit=5000", nil))
+
+	require.EqualValues(t, http.StatusBadRequest, w.Code)
+
+	var response apierror.Error
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	var fields []string
+	for _, field := range response.Fields {
+		fields = append(fields, field.In+"."+field.Field)
+	}
+	require.ElementsMatch(t, []string{"path.id", "query.limit"}, fields)
+}
+
+func TestRequestValidatorPassesValidRequest(t *testing.T) {
+	var reached string
+	router := newValidatedRouter(t, &reached)
+
+	body := `{"number": 1, "house_id": 1, "price": 100, "rooms": 2}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/flat/create", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.EqualValues(t, http.StatusOK, w.Code)
+	require.JSONEq(t, body, reached, "the handler must see the body the validator read")
+}
+
+func TestRequestValidatorIgnoresUndocumentedPaths(t *testing.T) {
+	reached := "not reached"
+	router := newValidatedRouter(t, &reached)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/undocumented", nil))
+
+	require.EqualValues(t, http.StatusOK, w.Code)
+	require.Empty(t, reached)
+}
@@ -0,0 +1,365 @@
+package router
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/graph"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/health"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/middleware"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/bulk"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/export"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// undocumented are the routes no OpenAPI spec describes: the probes, the metrics
+// and GraphQL, which has its own schema.
+var undocumented = []string{"/healthz", "/readyz", "/metrics", "/graphql"}
+
+// contractCase is a single request to an API version. Cases run in order against
+// one store, so later ones can rely on the objects created by earlier ones.
+type contractCase struct {
+	name   string
+	method string
+	path   string
+	role   string
+	body   string
+	// contentType of the body, application/json by default.
+	contentType string
+	status      int
+	// invalid marks requests that deliberately violate the spec, only their
+	// responses are validated.
+	invalid bool
+}
+
+// contractCases are the requests to the /v2 API, described by api.yaml.
+var contractCases = []contractCase{
+	{name: "dummy login", method: http.MethodGet, path: "/dummyLogin?user_type=client", status: http.StatusOK},
+	{name: "dummy login as admin", method: http.MethodGet, path: "/dummyLogin?user_type=admin", status: http.StatusBadRequest, invalid: true},
+
+	{name: "register", method: http.MethodPost, path: "/register", body: `{"email": "user@example.com", "password": "password123", "user_type": "client"}`, status: http.StatusOK},
+	{name: "register invalid email", method: http.MethodPost, path: "/register", body: `{"email": "user", "password": "password123", "user_type": "client"}`, status: http.StatusBadRequest, invalid: true},
+	{name: "register taken email", method: http.MethodPost, path: "/register", body: `{"email": "user@example.com", "password": "password123", "user_type": "client"}`, status: http.StatusConflict},
+	{name: "login", method: http.MethodPost, path: "/login", body: `{"id": 1, "password": "password123"}`, status: http.StatusOK},
+	{name: "login wrong password", method: http.MethodPost, path: "/login", body: `{"id": 1, "password": "password321"}`, status: http.StatusUnauthorized},
+	{name: "login unknown user", method: http.MethodPost, path: "/login", body: `{"id": 42, "password": "password123"}`, status: http.StatusNotFound},
+
+	{name: "create house", method: http.MethodPost, path: "/house/create", role: "moderator", body: `{"address": "Садовая улица, 15, Москва, 123456", "year": 2010, "developer": "Строительный трест"}`, status: http.StatusOK},
+	{name: "create house without developer", method: http.MethodPost, path: "/house/create", role: "moderator", body: `{"address": "Лесная улица, 7, Москва, 125196", "year": 2000}`, status: http.StatusOK},
+	{name: "create house as client", method: http.MethodPost, path: "/house/create", role: "client", body: `{"address": "Лесная улица, 7", "year": 2000}`, status: http.StatusForbidden},
+	{name: "create house without token", method: http.MethodPost, path: "/house/create", body: `{"address": "Лесная улица, 7", "year": 2000}`, status: http.StatusUnauthorized, invalid: true},
+	{name: "create house invalid year", method: http.MethodPost, path: "/house/create", role: "moderator", body: `{"address": "Лесная улица, 7", "year": "2000"}`, status: http.StatusBadRequest, invalid: true},
+
+	{name: "create flat", method: http.MethodPost, path: "/flat/create", role: "client", body: `{"number": 1, "house_id": 1, "price": 5000000, "rooms": 2}`, status: http.StatusOK},
+	{name: "create second flat", method: http.MethodPost, path: "/flat/create", role: "client", body: `{"number": 2, "house_id": 1, "price": 7000000, "rooms": 3}`, status: http.StatusOK},
+	{name: "create duplicate flat", method: http.MethodPost, path: "/flat/create", role: "client", body: `{"number": 1, "house_id": 1, "price": 5000000, "rooms": 2}`, status: http.StatusConflict},
+	{name: "create flat in unknown house", method: http.MethodPost, path: "/flat/create", role: "client", body: `{"number": 1, "house_id": 999, "price": 5000000, "rooms": 2}`, status: http.StatusNotFound},
+	{name: "create flat without rooms", method: http.MethodPost, path: "/flat/create", role: "client", body: `{"number": 3, "house_id": 1, "price": 5000000}`, status: http.StatusBadRequest, invalid: true},
+
+	{name: "approve flat", method: http.MethodPost, path: "/flat/update", role: "moderator", body: `{"id": 1, "status": "approved"}`, status: http.StatusOK},
+	{name: "moderate flat", method: http.MethodPost, path: "/flat/update", role: "moderator", body: `{"id": 2, "status": "on_moderate"}`, status: http.StatusOK},
+	{name: "update unknown flat", method: http.MethodPost, path: "/flat/update", role: "moderator", body: `{"id": 999, "status": "approved"}`, status: http.StatusNotFound},
+	{name: "update flat as client", method: http.MethodPost, path: "/flat/update", role: "client", body: `{"id": 1, "status": "approved"}`, status: http.StatusForbidden},
+	{name: "update flat unknown status", method: http.MethodPost, path: "/flat/update", role: "moderator", body: `{"id": 1, "status": "sold"}`, status: http.StatusBadRequest, invalid: true},
+
+	{name: "house flats as client", method: http.MethodGet, path: "/house/1", role: "client", status: http.StatusOK},
+	{name: "house flats page", method: http.MethodGet, path: "/house/1?limit=1&sort=price&order=desc", role: "moderator", status: http.StatusOK},
+	{name: "house flats filtered", method: http.MethodGet, path: "/house/1?status=on_moderate&rooms=3&price_from=1&price_to=10000000", role: "moderator", status: http.StatusOK},
+	{name: "house flats invalid cursor", method: http.MethodGet, path: "/house/1?cursor=bogus", role: "client", status: http.StatusBadRequest},
+	{name: "house flats invalid id", method: http.MethodGet, path: "/house/abc", role: "client", status: http.StatusBadRequest, invalid: true},
+	{name: "house flats without token", method: http.MethodGet, path: "/house/1", status: http.StatusUnauthorized, invalid: true},
+
+	{name: "search houses", method: http.MethodGet, path: "/house/search?q=Садовая&limit=5", role: "client", status: http.StatusOK},
+	{name: "search houses without query", method: http.MethodGet, path: "/house/search", role: "client", status: http.StatusBadRequest, invalid: true},
+
+	{name: "subscribe", method: http.MethodPost, path: "/house/1/subscribe", role: "client", body: `{"email": "user@example.com"}`, status: http.StatusOK},
+	{name: "subscribe to unknown house", method: http.MethodPost, path: "/house/999/subscribe", role: "client", body: `{"email": "user@example.com"}`, status: http.StatusNotFound},
+	{name: "subscribe invalid email", method: http.MethodPost, path: "/house/1/subscribe", role: "client", body: `{"email": "user"}`, status: http.StatusBadRequest, invalid: true},
+
+	{name: "search flats as client", method: http.MethodGet, path: "/flats", role: "client", status: http.StatusOK},
+	{name: "search flats filtered", method: http.MethodGet, path: "/flats?price_from=1&price_to=10000000&rooms=2&year_from=2000&year_to=2020&developer=Строительный%20трест&sort=price_desc&limit=1&offset=0", role: "moderator", status: http.StatusOK},
+	{name: "search flats unknown sort", method: http.MethodGet, path: "/flats?sort=cheapest", role: "client", status: http.StatusBadRequest, invalid: true},
+
+	{name: "import", method: http.MethodPost, path: "/import?format=csv", role: "moderator", contentType: "application/octet-stream", body: "kind,ref,address,year,house_ref,number,price,rooms\nhouse,A,\"Лесная улица, 9\",2000,,,,\nflat,,,,A,1,10000,2\n", status: http.StatusOK},
+	{name: "import with failed rows", method: http.MethodPost, path: "/import?format=ndjson&dry_run=true&chunk_size=1", role: "moderator", contentType: "application/octet-stream", body: `{"kind": "flat", "house_id": 1, "number": 1, "price": 10000, "rooms": 2}` + "\n" + `{"kind": "flat", "house_id": 1, "number": 5, "price": 10000, "rooms": 2}`, status: http.StatusOK},
+	{name: "import unknown column", method: http.MethodPost, path: "/import?format=csv", role: "moderator", contentType: "application/octet-stream", body: "kind,floor\nflat,2\n", status: http.StatusBadRequest},
+	{name: "export houses", method: http.MethodGet, path: "/export/houses?format=csv", role: "moderator", status: http.StatusOK},
+	{name: "export flats", method: http.MethodGet, path: "/export/flats?format=xlsx&status=approved", role: "moderator", status: http.StatusOK},
+	{name: "export flats as ndjson", method: http.MethodGet, path: "/export/flats?format=ndjson", role: "moderator", status: http.StatusOK},
+	{name: "export unknown format", method: http.MethodGet, path: "/export/flats?format=pdf", role: "moderator", status: http.StatusBadRequest, invalid: true},
+	{name: "export as client", method: http.MethodGet, path: "/export/houses?format=csv", role: "client", status: http.StatusForbidden},
+	{name: "import too large", method: http.MethodPost, path: "/import?format=csv", role: "moderator", contentType: "application/octet-stream", body: "kind\n" + strings.Repeat("house\n", 200), status: http.StatusRequestEntityTooLarge},
+	{name: "import as client", method: http.MethodPost, path: "/import?format=csv", role: "client", contentType: "application/octet-stream", body: "kind\nhouse\n", status: http.StatusForbidden},
+
+	{name: "set user role", method: http.MethodPut, path: "/user/1/role", role: "admin", body: `{"role": "moderator"}`, status: http.StatusOK},
+	{name: "set unknown role", method: http.MethodPut, path: "/user/1/role", role: "admin", body: `{"role": "owner"}`, status: http.StatusBadRequest},
+	{name: "set role of unknown user", method: http.MethodPut, path: "/user/42/role", role: "admin", body: `{"role": "client"}`, status: http.StatusNotFound},
+	{name: "set role as moderator", method: http.MethodPut, path: "/user/1/role", role: "moderator", body: `{"role": "admin"}`, status: http.StatusForbidden},
+}
+
+// v1ContractCases are the requests to the deprecated /v1 and unversioned API,
+// described by v1.yaml. Requests are not validated by the router there, so
+// invalid ones get the errors of the handlers.
+var v1ContractCases = []contractCase{
+	{name: "dummy login", method: http.MethodGet, path: "/dummyLogin?user_type=moderator", status: http.StatusOK},
+	{name: "dummy login as admin", method: http.MethodGet, path: "/dummyLogin?user_type=admin", status: http.StatusBadRequest, invalid: true},
+
+	{name: "register", method: http.MethodPost, path: "/register", body: `{"email": "user@example.com", "password": "password123", "user_type": "client"}`, status: http.StatusOK},
+	{name: "register invalid email", method: http.MethodPost, path: "/register", body: `{"email": "user", "password": "password123", "user_type": "client"}`, status: http.StatusBadRequest, invalid: true},
+	{name: "register taken email", method: http.MethodPost, path: "/register", body: `{"email": "user@example.com", "password": "password123", "user_type": "client"}`, status: http.StatusConflict},
+	{name: "login", method: http.MethodPost, path: "/login", body: `{"id": 1, "password": "password123"}`, status: http.StatusOK},
+	{name: "login wrong password", method: http.MethodPost, path: "/login", body: `{"id": 1, "password": "password321"}`, status: http.StatusUnauthorized},
+	{name: "login unknown user", method: http.MethodPost, path: "/login", body: `{"id": 42, "password": "password123"}`, status: http.StatusNotFound},
+
+	{name: "create house", method: http.MethodPost, path: "/house/create", role: "moderator", body: `{"address": "Садовая улица, 15, Москва, 123456", "year": 2010, "developer": "Строительный трест"}`, status: http.StatusOK},
+	{name: "create house as client", method: http.MethodPost, path: "/house/create", role: "client", body: `{"address": "Лесная улица, 7", "year": 2000}`, status: http.StatusForbidden},
+	{name: "create house without token", method: http.MethodPost, path: "/house/create", body: `{"address": "Лесная улица, 7", "year": 2000}`, status: http.StatusUnauthorized, invalid: true},
+	{name: "create house invalid year", method: http.MethodPost, path: "/house/create", role: "moderator", body: `{"address": "Лесная улица, 7", "year": "2000"}`, status: http.StatusBadRequest, invalid: true},
+
+	{name: "create flat", method: http.MethodPost, path: "/flat/create", role: "client", body: `{"number": 1, "house_id": 1, "price": 5000000, "rooms": 2}`, status: http.StatusOK},
+	{name: "create second flat", method: http.MethodPost, path: "/flat/create", role: "client", body: `{"number": 2, "house_id": 1, "price": 7000000, "rooms": 3}`, status: http.StatusOK},
+	{name: "create duplicate flat", method: http.MethodPost, path: "/flat/create", role: "client", body: `{"number": 1, "house_id": 1, "price": 5000000, "rooms": 2}`, status: http.StatusConflict},
+	{name: "create flat in unknown house", method: http.MethodPost, path: "/flat/create", role: "client", body: `{"number": 1, "house_id": 999, "price": 5000000, "rooms": 2}`, status: http.StatusNotFound},
+
+	{name: "approve flat", method: http.MethodPost, path: "/flat/update", role: "moderator", body: `{"id": 1, "status": "approved"}`, status: http.StatusOK},
+	{name: "update unknown flat", method: http.MethodPost, path: "/flat/update", role: "moderator", body: `{"id": 999, "status": "approved"}`, status: http.StatusNotFound},
+	{name: "update flat as client", method: http.MethodPost, path: "/flat/update", role: "client", body: `{"id": 1, "status": "approved"}`, status: http.StatusForbidden},
+
+	{name: "house flats as client", method: http.MethodGet, path: "/house/1", role: "client", status: http.StatusOK},
+	{name: "house flats page", method: http.MethodGet, path: "/house/1?limit=1&sort=price&order=desc", role: "moderator", status: http.StatusOK},
+	{name: "house flats invalid cursor", method: http.MethodGet, path: "/house/1?cursor=bogus", role: "client", status: http.StatusBadRequest},
+	{name: "house flats invalid id", method: http.MethodGet, path: "/house/abc", role: "client", status: http.StatusBadRequest, invalid: true},
+
+	{name: "search houses", method: http.MethodGet, path: "/house/search?q=Садовая&limit=5", role: "client", status: http.StatusOK},
+	{name: "subscribe", method: http.MethodPost, path: "/house/1/subscribe", role: "client", body: `{"email": "user@example.com"}`, status: http.StatusOK},
+	{name: "subscribe to unknown house", method: http.MethodPost, path: "/house/999/subscribe", role: "client", body: `{"email": "user@example.com"}`, status: http.StatusNotFound},
+	{name: "search flats", method: http.MethodGet, path: "/flats?price_from=1&rooms=2&sort=price_desc&limit=1", role: "client", status: http.StatusOK},
+	{name: "search flats unknown sort", method: http.MethodGet, path: "/flats?sort=cheapest", role: "client", status: http.StatusBadRequest, invalid: true},
+}
+
+// contractAPI is an API version: its cases are sent under every prefix it is
+// served at and checked against its spec.
+type contractAPI struct {
+	spec     string
+	load     func() (*openapi3.T, error)
+	prefixes []string
+	cases    []contractCase
+}
+
+var contractAPIs = []contractAPI{
+	{spec: "api.yaml", load: api.Load, prefixes: []string{"/v2"}, cases: contractCases},
+	{spec: "v1.yaml", load: api.LoadV1, prefixes: []string{"/v1", ""}, cases: v1ContractCases},
+}
+
+// TestContract replays the cases of every API version through router.New and
+// validates every request and response against the spec of the version. It
+// fails when a response has a status, content type, header or body the spec
+// does not describe, when a documented operation has no successful case and
+// when the router serves a route no spec documents.
+func TestContract(t *testing.T) {
+	// kin has no decoder for NDJSON, the exports are checked as plain text
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.PlainBodyDecoder)
+
+	docs := make(map[string]*openapi3.T)
+	for _, version := range contractAPIs {
+		doc, err := version.load()
+		require.NoError(t, err)
+		forbidAdditionalProperties(doc)
+		docs[version.spec] = doc
+
+		for _, prefix := range version.prefixes {
+			t.Run(cmp.Or(strings.TrimPrefix(prefix, "/"), "unversioned"), func(t *testing.T) {
+				testContract(t, version.spec, doc, prefix, version.cases)
+			})
+		}
+	}
+
+	tokens := auth.NewTokenManager(auth.Config{Secret: "test-secret", TokenTTL: time.Hour, Issuer: "test"})
+	err := chi.Walk(newMemoryRouter(t, tokens), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if slices.Contains(undocumented, route) {
+			return nil
+		}
+
+		spec, path := "v1.yaml", strings.TrimPrefix(route, "/v1")
+		if rest, ok := strings.CutPrefix(route, "/v2"); ok {
+			spec, path = "api.yaml", rest
+		}
+		if item := docs[spec].Paths.Value(path); item == nil || item.GetOperation(method) == nil {
+			return fmt.Errorf("%s %s is served but not documented in %s", method, route, spec)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// testContract sends cases under prefix to a new router and checks them against doc.
+func testContract(t *testing.T, spec string, doc *openapi3.T, prefix string, cases []contractCase) {
+	specRouter, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	tokens := auth.NewTokenManager(auth.Config{Secret: "test-secret", TokenTTL: time.Hour, Issuer: "test"})
+	handler := newMemoryRouter(t, tokens)
+
+	options := &openapi3filter.Options{
+		IncludeResponseStatus: true,
+		AuthenticationFunc:    bearerAuthenticator,
+	}
+
+	covered := make(map[string]bool)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, prefix+tc.path, strings.NewReader(tc.body))
+			if tc.body != "" {
+				contentType := tc.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				req.Header.Set("Content-Type", contentType)
+			}
+			if tc.role != "" {
+				token, err := tokens.Generate(1, tc.role, usecase.DefaultRoles[tc.role])
+				require.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			route, pathParams, err := specRouter.FindRoute(req)
+			require.NoError(t, err, "%s %s is not in %s", tc.method, tc.path, spec)
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+			if !tc.invalid {
+				// Validation consumes the body, so it is validated on a copy
+				validated := *input
+				validated.Request = req.Clone(req.Context())
+				validated.Request.Body = io.NopCloser(strings.NewReader(tc.body))
+				require.NoError(t, openapi3filter.ValidateRequest(context.Background(), &validated), "request does not match %s", spec)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, tc.status, w.Code, w.Body.String())
+
+			err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 w.Code,
+				Header:                 w.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+				Options:                options,
+			})
+			require.NoError(t, err, "response does not match %s", spec)
+
+			if w.Code < http.StatusBadRequest {
+				covered[operationKey(route.Method, route.Path)] = true
+			}
+		})
+	}
+
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			require.True(t, covered[operationKey(method, path)], "no successful contract case for %s %s", method, prefix+path)
+		}
+	}
+}
+
+func newMemoryRouter(t *testing.T, tokens *auth.TokenManager) *chi.Mux {
+	// The validator gets its own copy, the one under test is made stricter
+	spec, err := api.Load()
+	require.NoError(t, err)
+	validate, err := middleware.RequestValidator(spec)
+	require.NoError(t, err)
+
+	store := memory.NewStore()
+	users := auth.NewMemoryRepo(store, tokens)
+	houses := house.NewMemoryRepo(store)
+	flats := flat.NewMemoryRepo(store)
+	subscriptions := sender.NewMemoryRepo(store)
+	errs := slices.Concat(auth.Errors, sender.Errors, bulk.Errors)
+	graph, err := graph.NewHandler(graph.Config{MaxDepth: 8, MaxComplexity: 1000}, errs, houses, flats, subscriptions, users)
+	require.NoError(t, err)
+
+	return New(tokens, validate, errs,
+		auth.NewHandler(users, tokens),
+		house.NewHandler(houses),
+		flat.NewHandler(flats),
+		sender.NewHandler(subscriptions),
+		bulk.NewHandler(bulk.NewMemoryRepo(store), bulk.Config{MaxBytes: 1 << 10}),
+		export.NewHandler(export.NewMemoryRepo(store)),
+		graph,
+		health.NewHandler(),
+	)
+}
+
+// bearerAuthenticator only checks that a bearer token is sent, its validity is
+// up to the router under test.
+func bearerAuthenticator(_ context.Context, input *openapi3filter.AuthenticationInput) error {
+	if !strings.HasPrefix(input.RequestValidationInput.Request.Header.Get("Authorization"), "Bearer ") {
+		return fmt.Errorf("missing bearer token")
+	}
+	return nil
+}
+
+// forbidAdditionalProperties makes objects without an explicit additionalProperties
+// closed, so that renamed or undocumented fields are reported as drift.
+func forbidAdditionalProperties(doc *openapi3.T) {
+	visited := make(map[*openapi3.Schema]bool)
+
+	var visit func(ref *openapi3.SchemaRef)
+	visit = func(ref *openapi3.SchemaRef) {
+		if ref == nil || ref.Value == nil || visited[ref.Value] {
+			return
+		}
+		schema := ref.Value
+		visited[schema] = true
+
+		if schema.Type.Is(openapi3.TypeObject) && schema.AdditionalProperties.Has == nil && schema.AdditionalProperties.Schema == nil {
+			closed := false
+			schema.AdditionalProperties.Has = &closed
+		}
+		for _, property := range schema.Properties {
+			visit(property)
+		}
+		visit(schema.Items)
+	}
+
+	for _, item := range doc.Paths.Map() {
+		for _, operation := range item.Operations() {
+			if operation.RequestBody != nil {
+				for _, media := range operation.RequestBody.Value.Content {
+					visit(media.Schema)
+				}
+			}
+			for _, response := range operation.Responses.Map() {
+				for _, media := range response.Value.Content {
+					visit(media.Schema)
+				}
+			}
+		}
+	}
+}
+
+func operationKey(method, path string) string {
+	return method + " " + path
+}
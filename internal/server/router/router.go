@@ -1,36 +1,151 @@
 package router
 
 import (
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/metrics"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/graph"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/health"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/middleware"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/bulk"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/export"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
 	"github.com/go-chi/chi/v5"
+	"net/http"
+	"time"
 )
 
-func New(auth *auth.Handler, house *house.Handler, flat *flat.Handler, sender *sender.Handler) *chi.Mux {
+const successorVersion = "/v2"
+
+var can = middleware.RequirePermission
+
+var (
+	v1DeprecatedAt = time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	v1SunsetAt     = time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// New builds the HTTP router. validate checks /v2 requests against the API
+// specification after authentication, see middleware.RequestValidator. errors
+// resolves the errors the /v2 handlers return.
+func New(tokens middleware.TokenParser, validate func(http.Handler) http.Handler, errors apierror.Mapper, auth *auth.Handler, house *house.Handler, flat *flat.Handler, sender *sender.Handler, bulk *bulk.Handler, export *export.Handler, graph *graph.Handler, health *health.Handler) *chi.Mux {
 	router := chi.NewRouter()
+	router.Use(middleware.RequestID, middleware.Tracing, middleware.Logger, middleware.Metrics)
+
+	// Probes and metrics, no auth
+	router.Get("/healthz", health.Liveness)
+	router.Get("/readyz", health.Readiness)
+	router.Handle("/metrics", metrics.Handler())
+
+	// Unversioned paths are served with the v1 shapes for existing clients
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.Deprecated(v1DeprecatedAt, v1SunsetAt, successorVersion))
+		v1(r, tokens, auth, house, flat, sender)
+	})
 
+	router.Route("/v1", func(r chi.Router) {
+		r.Use(middleware.Deprecated(v1DeprecatedAt, v1SunsetAt, successorVersion))
+		v1(r, tokens, auth, house, flat, sender)
+	})
+
+	router.Route("/v2", func(r chi.Router) {
+		v2(r, tokens, validate, errors, auth, house, flat, sender, bulk, export)
+	})
+
+	// GraphQL, the resolvers check the permissions of the fields they serve
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.TokenAuthenticator(tokens), can(usecase.PermHouseRead))
+		r.Post("/graphql", graph.ServeHTTP)
+	})
+
+	return router
+}
+
+func v1(router chi.Router, tokens middleware.TokenParser, auth *auth.Handler, house *house.Handler, flat *flat.Handler, sender *sender.Handler) {
 	// No auth
 	router.Get("/dummyLogin", auth.DummyLogin)
 	router.Post("/login", auth.Login)
 	router.Post("/register", auth.Register)
 
-	// Auth only
+	// Auth, each route needs its own permission
 	router.Group(func(r chi.Router) {
-		r.Use(middleware.TokenAuthenticator, middleware.AuthOnly)
-		r.Get("/house/{id}", house.Flats)
-		r.Post("/house/{id}/subscribe", sender.Subscribe)
-		r.Post("/flat/create", flat.Create)
+		r.Use(middleware.TokenAuthenticator(tokens))
+		r.With(can(usecase.PermHouseRead)).Get("/house/search", house.Search)
+		r.With(can(usecase.PermHouseRead)).Get("/house/{id}", house.Flats)
+		r.With(can(usecase.PermHouseSubscribe)).Post("/house/{id}/subscribe", sender.Subscribe)
+		r.With(can(usecase.PermFlatCreate)).Post("/flat/create", flat.Create)
+		r.With(can(usecase.PermHouseRead)).Get("/flats", flat.Search)
+		r.With(can(usecase.PermHouseCreate)).Post("/house/create", house.Create)
+		r.With(can(usecase.PermFlatModerate)).Post("/flat/update", flat.Update)
 	})
+}
+
+// server serves /v2 from the handlers of every service. The aliases give the
+// embedded fields distinct names.
+type (
+	authHandler   = auth.Handler
+	houseHandler  = house.Handler
+	flatHandler   = flat.Handler
+	senderHandler = sender.Handler
+	bulkHandler   = bulk.Handler
+	exportHandler = export.Handler
+)
+
+type server struct {
+	*authHandler
+	*houseHandler
+	*flatHandler
+	*senderHandler
+	*bulkHandler
+	*exportHandler
+}
+
+var _ api.StrictServerInterface = server{}
 
-	// Moderation only
+func v2(router chi.Router, tokens middleware.TokenParser, validate func(http.Handler) http.Handler, errors apierror.Mapper, auth *auth.Handler, house *house.Handler, flat *flat.Handler, sender *sender.Handler, bulk *bulk.Handler, export *export.Handler) {
+	strict := api.NewStrictHandlerWithOptions(server{auth, house, flat, sender, bulk, export}, nil, api.StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, _ error) {
+			apierror.Write(w, r, apierror.ErrInvalidPayload)
+		},
+		ResponseErrorHandlerFunc: errors.Write,
+	})
+	handlers := api.ServerInterfaceWrapper{
+		Handler: strict,
+		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			apierror.Write(w, r, apierror.InvalidParameter(err.Error()))
+		},
+	}
+
+	// No auth
 	router.Group(func(r chi.Router) {
-		r.Use(middleware.TokenAuthenticator, middleware.ModerationOnly)
-		r.Post("/house/create", house.Create)
-		r.Post("/flat/update", flat.Update)
+		r.Use(validate)
+		r.Get("/dummyLogin", handlers.IssueDummyToken)
+		r.Post("/login", handlers.LoginUser)
+		r.Post("/register", handlers.RegisterUser)
 	})
 
-	return router
+	// Auth, each route needs its own permission. Requests are validated after
+	// the permission check, so a forbidden request gets 403 rather than 400.
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.TokenAuthenticator(tokens))
+		allow := func(permission usecase.Permission) chi.Router {
+			return r.With(can(permission), validate)
+		}
+
+		allow(usecase.PermHouseRead).Get("/house/search", handlers.SearchHouses)
+		allow(usecase.PermHouseRead).Get("/house/{id}", handlers.ListHouseFlats)
+		allow(usecase.PermHouseSubscribe).Post("/house/{id}/subscribe", handlers.SubscribeToHouse)
+		allow(usecase.PermFlatCreate).Post("/flat/create", handlers.CreateFlat)
+		allow(usecase.PermHouseRead).Get("/flats", handlers.SearchFlats)
+		allow(usecase.PermHouseCreate).Post("/house/create", handlers.CreateHouse)
+		allow(usecase.PermFlatModerate).Post("/flat/update", handlers.UpdateFlat)
+		// The body is limited before validate reads it into memory
+		r.With(can(usecase.PermDataImport), middleware.MaxBodySize(bulk.MaxBytes()), validate).Post("/import", handlers.ImportHousesAndFlats)
+		allow(usecase.PermDataExport).Get("/export/houses", handlers.ExportHouses)
+		allow(usecase.PermDataExport).Get("/export/flats", handlers.ExportFlats)
+		allow(usecase.PermUserManage).Put("/user/{id}/role", handlers.SetUserRole)
+	})
 }
@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"context"
+	housingv1 "github.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/middleware"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"net/http"
+	"strings"
+)
+
+const (
+	claimsKey = "claims"
+
+	authorization = "authorization"
+)
+
+var errAuthorizationMissing = apierror.New(http.StatusUnauthorized, apierror.CodeAuthorizationMissing, "Authorization metadata missing")
+
+// permissions maps every method to the permission it needs, the same one
+// middleware.RequirePermission checks for the matching HTTP route. Methods
+// missing here are denied.
+var permissions = map[string]usecase.Permission{
+	housingv1.HouseService_CreateHouse_FullMethodName:           usecase.PermHouseCreate,
+	housingv1.HouseService_SearchHouses_FullMethodName:          usecase.PermHouseRead,
+	housingv1.HouseService_ListHouseFlats_FullMethodName:        usecase.PermHouseRead,
+	housingv1.FlatService_CreateFlat_FullMethodName:             usecase.PermFlatCreate,
+	housingv1.FlatService_SearchFlats_FullMethodName:            usecase.PermHouseRead,
+	housingv1.ModerationService_UpdateFlatStatus_FullMethodName: usecase.PermFlatModerate,
+	housingv1.SubscriptionService_Subscribe_FullMethodName:      usecase.PermHouseSubscribe,
+}
+
+// authenticator verifies the bearer token in the authorization metadata with
+// parser, checks the permission the method needs and stores the claims in the
+// context the same way middleware.TokenAuthenticator does.
+func authenticator(parser middleware.TokenParser) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(authorization)
+		if len(values) == 0 || values[0] == "" {
+			return nil, errAuthorizationMissing
+		}
+
+		claims, err := parser.Parse(strings.TrimPrefix(values[0], "Bearer "))
+		if err != nil {
+			return nil, err
+		}
+
+		permission, ok := permissions[info.FullMethod]
+		if !ok || !claims.Can(permission) {
+			return nil, apierror.ErrForbidden
+		}
+
+		ctx = context.WithValue(ctx, claimsKey, claims)
+		ctx = logger.With(ctx, "user_id", claims.UserID, "role", claims.Role)
+		return handler(ctx, req)
+	}
+}
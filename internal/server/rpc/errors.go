@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"strconv"
+)
+
+// errorDomain is the ErrorInfo domain of the API error codes, see apierror.Code.
+const errorDomain = "backend-bootcamp"
+
+var codesByStatus = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// errorStatus converts the errors returned by the services to gRPC statuses with
+// mapper, the same way apierror.Mapper.Write converts them to HTTP responses.
+// The API error code is sent as the reason of an ErrorInfo detail.
+func errorStatus(mapper apierror.Mapper) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return nil, toStatus(ctx, mapper, err)
+	}
+}
+
+func toStatus(ctx context.Context, mapper apierror.Mapper, err error) error {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		return err
+	}
+
+	apiErr := mapper.From(err)
+	if apiErr.Status >= http.StatusInternalServerError {
+		logger.FromContext(ctx).Error(apiErr.Message, "error", apiErr.Cause, "code", apiErr.Code)
+	}
+
+	code, ok := codesByStatus[apiErr.Status]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	st := status.New(code, apiErr.Message)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: strconv.Itoa(int(apiErr.Code)), Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	housingv1 "github.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+var statuses = map[housingv1.FlatStatus]string{
+	housingv1.FlatStatus_FLAT_STATUS_CREATED:     "created",
+	housingv1.FlatStatus_FLAT_STATUS_ON_MODERATE: "on_moderate",
+	housingv1.FlatStatus_FLAT_STATUS_APPROVED:    "approved",
+	housingv1.FlatStatus_FLAT_STATUS_DECLINED:    "declined",
+}
+
+type flatServer struct {
+	housingv1.UnimplementedFlatServiceServer
+	repo flat.Flat
+}
+
+func (s flatServer) CreateFlat(ctx context.Context, request *housingv1.CreateFlatRequest) (*housingv1.Flat, error) {
+	req := usecase.FlatCreateRequest{
+		Number:  int(request.GetNumber()),
+		HouseID: int(request.GetHouseId()),
+		Price:   int(request.GetPrice()),
+		Rooms:   int(request.GetRooms()),
+	}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	response, err := s.repo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return toFlat(response), nil
+}
+
+func (s flatServer) SearchFlats(ctx context.Context, request *housingv1.SearchFlatsRequest) (*housingv1.SearchFlatsResponse, error) {
+	claims, ok := ctx.Value(claimsKey).(*auth.Claims)
+	if !ok {
+		return nil, apierror.ErrMissingClaims
+	}
+
+	req := usecase.FlatSearchRequest{
+		PriceFrom:    int(request.GetPriceFrom()),
+		PriceTo:      int(request.GetPriceTo()),
+		Rooms:        int(request.GetRooms()),
+		YearFrom:     int(request.GetYearFrom()),
+		YearTo:       int(request.GetYearTo()),
+		Developer:    request.GetDeveloper(),
+		Sort:         request.GetSort(),
+		Limit:        int(request.GetLimit()),
+		Offset:       int(request.GetOffset()),
+		ApprovedOnly: !claims.Can(usecase.PermFlatModerate),
+	}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	response, err := s.repo.Search(ctx, req)
+	if err != nil {
+		return nil, apierror.Internal("Failed to search flats", err)
+	}
+
+	return &housingv1.SearchFlatsResponse{
+		Flats:  toFlats(response.Flats),
+		Total:  int32(response.Total),
+		Limit:  int32(response.Limit),
+		Offset: int32(response.Offset),
+	}, nil
+}
+
+func toFlat(f usecase.FlatResponse) *housingv1.Flat {
+	flat := &housingv1.Flat{
+		Id:      int64(f.ID),
+		HouseId: int64(f.HouseID),
+		Number:  int32(f.Number),
+		Price:   int32(f.Price),
+		Rooms:   int32(f.Rooms),
+	}
+	for status, name := range statuses {
+		if name == f.Status {
+			flat.Status = status
+		}
+	}
+	return flat
+}
+
+func toFlats(flats []usecase.FlatResponse) []*housingv1.Flat {
+	result := make([]*housingv1.Flat, 0, len(flats))
+	for _, f := range flats {
+		result = append(result, toFlat(f))
+	}
+	return result
+}
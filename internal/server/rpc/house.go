@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"cmp"
+	"context"
+	housingv1 "github.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type houseServer struct {
+	housingv1.UnimplementedHouseServiceServer
+	repo house.House
+}
+
+func (s houseServer) CreateHouse(ctx context.Context, request *housingv1.CreateHouseRequest) (*housingv1.House, error) {
+	req := usecase.HouseCreateRequest{
+		Address:   request.GetAddress(),
+		Year:      int(request.GetYear()),
+		Developer: request.GetDeveloper(),
+	}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	response, err := s.repo.Create(ctx, req)
+	if err != nil {
+		return nil, apierror.Internal("Failed to create house", err)
+	}
+
+	return toHouse(response), nil
+}
+
+func (s houseServer) SearchHouses(ctx context.Context, request *housingv1.SearchHousesRequest) (*housingv1.SearchHousesResponse, error) {
+	req := usecase.HouseSearchRequest{Query: request.GetQuery(), Limit: int(request.GetLimit())}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	houses, err := s.repo.Search(ctx, req)
+	if err != nil {
+		return nil, apierror.Internal("Failed to search houses", err)
+	}
+
+	response := &housingv1.SearchHousesResponse{Houses: make([]*housingv1.House, 0, len(houses))}
+	for _, h := range houses {
+		response.Houses = append(response.Houses, toHouse(h))
+	}
+	return response, nil
+}
+
+func (s houseServer) ListHouseFlats(ctx context.Context, request *housingv1.ListHouseFlatsRequest) (*housingv1.ListHouseFlatsResponse, error) {
+	claims, ok := ctx.Value(claimsKey).(*auth.Claims)
+	if !ok {
+		return nil, apierror.ErrMissingClaims
+	}
+
+	req := usecase.HouseFlatsRequest{
+		Cursor:    request.GetCursor(),
+		Limit:     cmp.Or(int(request.GetLimit()), usecase.DefaultHouseFlatsLimit),
+		Sort:      request.GetSort(),
+		Order:     request.GetOrder(),
+		Status:    statuses[request.GetStatus()],
+		Rooms:     int(request.GetRooms()),
+		PriceFrom: int(request.GetPriceFrom()),
+		PriceTo:   int(request.GetPriceTo()),
+	}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	var (
+		flats usecase.HouseFlats
+		err   error
+	)
+	houseID := int(request.GetHouseId())
+	if claims.Can(usecase.PermFlatModerate) {
+		flats, err = s.repo.ModeratorFlats(ctx, houseID, req)
+	} else {
+		flats, err = s.repo.ClientFlats(ctx, houseID, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &housingv1.ListHouseFlatsResponse{Flats: toFlats(flats.Flat), NextCursor: flats.NextCursor}, nil
+}
+
+func toHouse(h usecase.House) *housingv1.House {
+	return &housingv1.House{
+		Id:        int64(h.ID),
+		Address:   h.Address,
+		Year:      int32(h.Year),
+		Developer: h.Developer,
+		CreatedAt: timestamppb.New(h.CreatedAt),
+		UpdatedAt: timestamppb.New(h.UpdatedAt),
+	}
+}
@@ -0,0 +1,33 @@
+package rpc
+
+import (
+	"context"
+	housingv1 "github.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+type moderationServer struct {
+	housingv1.UnimplementedModerationServiceServer
+	repo flat.Flat
+}
+
+func (s moderationServer) UpdateFlatStatus(ctx context.Context, request *housingv1.UpdateFlatStatusRequest) (*housingv1.Flat, error) {
+	// created is not a moderation outcome, Validate rejects it along with unknown statuses
+	req := usecase.FlatUpdateRequest{ID: int(request.GetId()), Status: statuses[request.GetStatus()]}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+	if claims, ok := ctx.Value(claimsKey).(*auth.Claims); ok {
+		req.ModeratorID = claims.UserID
+	}
+
+	response, err := s.repo.Update(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return toFlat(response), nil
+}
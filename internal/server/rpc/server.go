@@ -0,0 +1,27 @@
+// Package rpc serves the gRPC API described in api/housing/v1 on top of the
+// same repositories as the HTTP handlers.
+package rpc
+
+import (
+	housingv1 "github.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/middleware"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
+	"google.golang.org/grpc"
+)
+
+// New builds the gRPC server. Every method needs a bearer token in the
+// authorization metadata, see authenticator. errors resolves the errors of the
+// services, see errorStatus.
+func New(tokens middleware.TokenParser, errors apierror.Mapper, houses house.House, flats flat.Flat, subscriber sender.Subscriber) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(errorStatus(errors), authenticator(tokens)))
+
+	housingv1.RegisterHouseServiceServer(server, houseServer{repo: houses})
+	housingv1.RegisterFlatServiceServer(server, flatServer{repo: flats})
+	housingv1.RegisterModerationServiceServer(server, moderationServer{repo: flats})
+	housingv1.RegisterSubscriptionServiceServer(server, subscriptionServer{repo: subscriber})
+
+	return server
+}
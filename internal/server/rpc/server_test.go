@@ -0,0 +1,154 @@
+package rpc
+
+import (
+	"context"
+	housingv1 "github.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type clients struct {
+	tokens     *auth.TokenManager
+	houses     housingv1.HouseServiceClient
+	flats      housingv1.FlatServiceClient
+	moderation housingv1.ModerationServiceClient
+	subscribe  housingv1.SubscriptionServiceClient
+}
+
+func newClients(t *testing.T) clients {
+	store := memory.NewStore()
+	tokens := auth.NewTokenManager(auth.Config{Secret: "test-secret", TokenTTL: time.Hour, Issuer: "test"})
+	server := New(tokens, slices.Concat(auth.Errors, house.Errors, flat.Errors, sender.Errors), house.NewMemoryRepo(store), flat.NewMemoryRepo(store), sender.NewMemoryRepo(store))
+
+	listener := bufconn.Listen(1 << 20)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return clients{
+		tokens:     tokens,
+		houses:     housingv1.NewHouseServiceClient(conn),
+		flats:      housingv1.NewFlatServiceClient(conn),
+		moderation: housingv1.NewModerationServiceClient(conn),
+		subscribe:  housingv1.NewSubscriptionServiceClient(conn),
+	}
+}
+
+func (c clients) as(t *testing.T, role string) context.Context {
+	token, err := c.tokens.Generate(1, role, usecase.DefaultRoles[role])
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), authorization, "Bearer "+token)
+}
+
+func requireStatus(t *testing.T, err error, code codes.Code, apiCode apierror.Code) {
+	st, ok := status.FromError(err)
+	require.True(t, ok, "expected a gRPC status, got %v", err)
+	require.Equal(t, code, st.Code(), st.Message())
+
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, strconv.Itoa(int(apiCode)), info.GetReason())
+}
+
+func TestAuthentication(t *testing.T) {
+	c := newClients(t)
+
+	_, err := c.houses.SearchHouses(context.Background(), &housingv1.SearchHousesRequest{Query: "Москва"})
+	requireStatus(t, err, codes.Unauthenticated, apierror.CodeAuthorizationMissing)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), authorization, "Bearer garbage")
+	_, err = c.houses.SearchHouses(ctx, &housingv1.SearchHousesRequest{Query: "Москва"})
+	requireStatus(t, err, codes.Unauthenticated, apierror.CodeTokenInvalid)
+
+	_, err = c.houses.SearchHouses(c.as(t, "guest"), &housingv1.SearchHousesRequest{Query: "Москва"})
+	requireStatus(t, err, codes.PermissionDenied, apierror.CodeForbidden)
+}
+
+func TestPermissions(t *testing.T) {
+	c := newClients(t)
+	request := &housingv1.CreateHouseRequest{Address: "Лесная улица, 7, Москва", Year: 2000}
+
+	_, err := c.houses.CreateHouse(c.as(t, usecase.RoleClient), request)
+	requireStatus(t, err, codes.PermissionDenied, apierror.CodeForbidden)
+
+	_, err = c.moderation.UpdateFlatStatus(c.as(t, usecase.RoleClient), &housingv1.UpdateFlatStatusRequest{Id: 1, Status: housingv1.FlatStatus_FLAT_STATUS_APPROVED})
+	requireStatus(t, err, codes.PermissionDenied, apierror.CodeForbidden)
+
+	created, err := c.houses.CreateHouse(c.as(t, usecase.RoleModerator), request)
+	require.NoError(t, err)
+	require.Positive(t, created.GetId())
+	require.Equal(t, request.GetAddress(), created.GetAddress())
+	require.NotNil(t, created.GetCreatedAt())
+}
+
+func TestFlatModeration(t *testing.T) {
+	c := newClients(t)
+
+	created, err := c.houses.CreateHouse(c.as(t, usecase.RoleModerator), &housingv1.CreateHouseRequest{Address: "Лесная улица, 7, Москва", Year: 2000})
+	require.NoError(t, err)
+
+	_, err = c.subscribe.Subscribe(c.as(t, usecase.RoleClient), &housingv1.SubscribeRequest{HouseId: created.GetId(), Email: "user@example.com"})
+	require.NoError(t, err)
+
+	f, err := c.flats.CreateFlat(c.as(t, usecase.RoleClient), &housingv1.CreateFlatRequest{HouseId: created.GetId(), Number: 1, Price: 1000, Rooms: 2})
+	require.NoError(t, err)
+	require.Equal(t, housingv1.FlatStatus_FLAT_STATUS_CREATED, f.GetStatus())
+
+	listed, err := c.houses.ListHouseFlats(c.as(t, usecase.RoleClient), &housingv1.ListHouseFlatsRequest{HouseId: created.GetId()})
+	require.NoError(t, err)
+	require.Empty(t, listed.GetFlats(), "clients only see approved flats")
+
+	_, err = c.moderation.UpdateFlatStatus(c.as(t, usecase.RoleModerator), &housingv1.UpdateFlatStatusRequest{Id: f.GetId(), Status: housingv1.FlatStatus_FLAT_STATUS_CREATED})
+	requireStatus(t, err, codes.InvalidArgument, apierror.CodeValidation)
+
+	approved, err := c.moderation.UpdateFlatStatus(c.as(t, usecase.RoleModerator), &housingv1.UpdateFlatStatusRequest{Id: f.GetId(), Status: housingv1.FlatStatus_FLAT_STATUS_APPROVED})
+	require.NoError(t, err)
+	require.Equal(t, housingv1.FlatStatus_FLAT_STATUS_APPROVED, approved.GetStatus())
+
+	found, err := c.flats.SearchFlats(c.as(t, usecase.RoleClient), &housingv1.SearchFlatsRequest{Rooms: 2})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, found.GetTotal())
+	require.Equal(t, f.GetId(), found.GetFlats()[0].GetId())
+}
+
+func TestErrors(t *testing.T) {
+	c := newClients(t)
+
+	_, err := c.flats.CreateFlat(c.as(t, usecase.RoleClient), &housingv1.CreateFlatRequest{HouseId: 1, Number: 1, Rooms: 2})
+	requireStatus(t, err, codes.InvalidArgument, apierror.CodeValidation)
+
+	_, err = c.flats.CreateFlat(c.as(t, usecase.RoleClient), &housingv1.CreateFlatRequest{HouseId: 404, Number: 1, Price: 1000, Rooms: 2})
+	requireStatus(t, err, codes.NotFound, apierror.CodeHouseNotFound)
+
+	_, err = c.moderation.UpdateFlatStatus(c.as(t, usecase.RoleModerator), &housingv1.UpdateFlatStatusRequest{Id: 404, Status: housingv1.FlatStatus_FLAT_STATUS_DECLINED})
+	requireStatus(t, err, codes.NotFound, apierror.CodeFlatNotFound)
+}
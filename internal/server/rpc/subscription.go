@@ -0,0 +1,27 @@
+package rpc
+
+import (
+	"context"
+	housingv1 "github.com/NRKA/backend-bootcamp-assignment-2024/api/housing/v1"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+type subscriptionServer struct {
+	housingv1.UnimplementedSubscriptionServiceServer
+	repo sender.Subscriber
+}
+
+func (s subscriptionServer) Subscribe(ctx context.Context, request *housingv1.SubscribeRequest) (*housingv1.SubscribeResponse, error) {
+	email := usecase.Subscribe{Email: request.GetEmail()}
+	if err := email.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	if err := s.repo.Subscribe(ctx, int(request.GetHouseId()), email); err != nil {
+		return nil, err
+	}
+
+	return &housingv1.SubscribeResponse{}, nil
+}
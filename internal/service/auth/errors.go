@@ -1,6 +1,10 @@
 package auth
 
-import "errors"
+import (
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"net/http"
+)
 
 var (
 	ErrUserNotFound     = errors.New("user not found")
@@ -8,4 +12,17 @@ var (
 	ErrTokenNotValidYet = errors.New("token not valid yet")
 	ErrTokenInvalid     = errors.New("token is invalid")
 	ErrInvalidPassword  = errors.New("invalid password")
+	ErrEmailTaken       = errors.New("email is already registered")
+	ErrUnknownRole      = errors.New("unknown role")
 )
+
+// Errors maps the errors of the package to their API representation.
+var Errors = apierror.Mapper{
+	apierror.Map(ErrUserNotFound, http.StatusNotFound, apierror.CodeUserNotFound, "User not found"),
+	apierror.Map(ErrInvalidPassword, http.StatusUnauthorized, apierror.CodeInvalidPassword, "Invalid password"),
+	apierror.Map(ErrUnknownRole, http.StatusBadRequest, apierror.CodeUnknownRole, "Unknown role"),
+	apierror.Map(ErrEmailTaken, http.StatusConflict, apierror.CodeEmailTaken, "Email is already registered"),
+	apierror.Map(ErrTokenExpired, http.StatusUnauthorized, apierror.CodeTokenExpired, "Token expired"),
+	apierror.Map(ErrTokenNotValidYet, http.StatusUnauthorized, apierror.CodeTokenNotValidYet, "Token not valid yet"),
+	apierror.Map(ErrTokenInvalid, http.StatusUnauthorized, apierror.CodeTokenInvalid, "Invalid token"),
+}
@@ -3,16 +3,13 @@ package auth
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
-	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
 	"net/http"
 )
 
 const (
-	userType  = "user_type"
-	client    = "client"
-	moderator = "moderator"
+	userType = "user_type"
 
 	id = 1
 )
@@ -20,89 +17,107 @@ const (
 type Authorizer interface {
 	Register(ctx context.Context, login usecase.CreateUserRequest) (usecase.CreateUserResponse, error)
 	Login(ctx context.Context, login usecase.LoginRequest) (usecase.LoginResponse, error)
+	// Permissions returns the permissions granted to role, none if the role is unknown.
+	Permissions(ctx context.Context, role string) ([]usecase.Permission, error)
+	// SetUserType changes the role of a user, ErrUnknownRole if there is no such role.
+	SetUserType(ctx context.Context, id int, userType string) error
 }
 
 type Handler struct {
-	repo Authorizer
+	repo   Authorizer
+	tokens *TokenManager
 }
 
-func NewHandler(db *postgres.Database) *Handler {
-	return &Handler{repo: NewRepo(db)}
+func NewHandler(repo Authorizer, tokens *TokenManager) *Handler {
+	return &Handler{repo: repo, tokens: tokens}
 }
 
 func (auth *Handler) DummyLogin(w http.ResponseWriter, r *http.Request) {
-	role := r.URL.Query().Get(userType)
-	if role != client && role != moderator {
-		http.Error(w, "Invalid role: Invalid request or missing user_type", http.StatusBadRequest)
+	token, err := auth.dummyToken(r.Context(), r.URL.Query().Get(userType))
+	if err != nil {
+		Errors.Write(w, r, err)
 		return
 	}
 
-	token, err := GenerateToken(id, role)
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(usecase.LoginResponse{Token: token}); err != nil {
+		Errors.Write(w, r, apierror.Internal("Failed to write response", err))
+	}
+}
+
+// dummyToken issues a token for a test user with role, which must be one of
+// the roles open to registration.
+func (auth *Handler) dummyToken(ctx context.Context, role string) (string, error) {
+	if role != usecase.RoleClient && role != usecase.RoleModerator {
+		return "", apierror.InvalidParameter("Invalid role: Invalid request or missing user_type")
+	}
+
+	permissions, err := auth.repo.Permissions(ctx, role)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := auth.tokens.Generate(id, role, permissions)
 	if err != nil {
-		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+		return "", apierror.Internal("Failed to generate token", err)
+	}
+
+	return token, nil
+}
+
+func (auth *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	response, ok := auth.register(w, r)
+	if !ok {
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err = json.NewEncoder(w).Encode(usecase.LoginResponse{Token: token}); err != nil {
-		http.Error(w, "Failed to write response", http.StatusInternalServerError)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		Errors.Write(w, r, apierror.Internal("Failed to write response", err))
 	}
 }
 
-func (auth *Handler) Register(w http.ResponseWriter, r *http.Request) {
+func (auth *Handler) register(w http.ResponseWriter, r *http.Request) (usecase.CreateUserResponse, bool) {
 	var req usecase.CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
-		return
+		Errors.Write(w, r, apierror.ErrInvalidPayload)
+		return usecase.CreateUserResponse{}, false
 	}
 
 	if err := req.Validate(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		Errors.Write(w, r, apierror.Validation(err))
+		return usecase.CreateUserResponse{}, false
 	}
 
 	response, err := auth.repo.Register(r.Context(), req)
 	if err != nil {
-		http.Error(w, "Failed to create user", http.StatusInternalServerError)
-		return
+		Errors.Write(w, r, err)
+		return usecase.CreateUserResponse{}, false
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err = json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to write response", http.StatusInternalServerError)
-	}
+	return response, true
 }
 
 func (auth *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req usecase.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		Errors.Write(w, r, apierror.ErrInvalidPayload)
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		Errors.Write(w, r, apierror.Validation(err))
 		return
 	}
 
 	response, err := auth.repo.Login(r.Context(), req)
 	if err != nil {
-		if errors.Is(err, ErrUserNotFound) {
-			http.Error(w, "User not found", http.StatusNotFound)
-			return
-		}
-
-		if errors.Is(err, ErrInvalidPassword) {
-			http.Error(w, "Invalid password", http.StatusUnauthorized)
-			return
-		}
-
-		http.Error(w, "Failed to login", http.StatusBadRequest)
+		Errors.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err = json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to write response", http.StatusInternalServerError)
+		Errors.Write(w, r, apierror.Internal("Failed to write response", err))
 	}
 }
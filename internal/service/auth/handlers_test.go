@@ -5,40 +5,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
-	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
-	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/suite"
+	"io"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
+	"time"
 )
 
 type authHandlerSuite struct {
 	suite.Suite
-	db      *postgres.Database
 	handler *Handler
 }
 
-func TestAuthSuite(t *testing.T) {
+func TestAuthHandlerSuite(t *testing.T) {
 	suite.Run(t, new(authHandlerSuite))
 }
 
-func (suite *authHandlerSuite) SetupSuite() {
-	db, err := postgres.NewDB(context.Background(), suite.fromEnv())
-	suite.Require().NoError(err)
-
-	suite.db = db
-	suite.handler = NewHandler(db)
-}
-
-func (suite *authHandlerSuite) TearDownSuite() {
-	suite.db.GetPool().Close()
-}
-
 func (suite *authHandlerSuite) SetupTest() {
-	suite.clearTestDB(suite.db)
+	tokens := NewTokenManager(Config{Secret: "test-secret", TokenTTL: time.Hour, Issuer: "test"})
+	suite.handler = NewHandler(NewMemoryRepo(memory.NewStore(), tokens), tokens)
 }
 
 func (suite *authHandlerSuite) TestDummyLoginClientSuccess() {
@@ -81,8 +71,9 @@ func (suite *authHandlerSuite) TestDummyLoginInvalidRole() {
 	resp := w.Result()
 	suite.Require().EqualValues(http.StatusBadRequest, resp.StatusCode)
 
-	expectedErrorMessage := "Invalid role: Invalid request or missing user_type\n"
-	suite.Require().EqualValues(expectedErrorMessage, w.Body.String())
+	response := suite.decodeError(w.Body)
+	suite.Require().EqualValues("Invalid role: Invalid request or missing user_type", response.Message)
+	suite.Require().EqualValues(apierror.CodeInvalidParameter, response.Code)
 }
 
 func (suite *authHandlerSuite) TestRegisterFailDecoding() {
@@ -95,8 +86,9 @@ func (suite *authHandlerSuite) TestRegisterFailDecoding() {
 	resp := w.Result()
 	suite.Require().EqualValues(http.StatusBadRequest, resp.StatusCode)
 
-	expectedErrorMessage := "Invalid request payload\n"
-	suite.Require().EqualValues(expectedErrorMessage, w.Body.String())
+	response := suite.decodeError(w.Body)
+	suite.Require().EqualValues("Invalid request payload", response.Message)
+	suite.Require().EqualValues(apierror.CodeInvalidPayload, response.Code)
 }
 
 func (suite *authHandlerSuite) TestRegisterFailValidation() {
@@ -114,7 +106,9 @@ func (suite *authHandlerSuite) TestRegisterFailValidation() {
 	suite.Require().EqualValues(http.StatusBadRequest, resp.StatusCode)
 
 	expectedErrorMessage := "Key: 'CreateUserRequest.Email' Error:Field validation for 'Email' failed on the 'email' tag\nKey: 'CreateUserRequest.Password' Error:Field validation for 'Password' failed on the 'min' tag\nKey: 'CreateUserRequest.UserType' Error:Field validation for 'UserType' failed on the 'oneof' tag"
-	suite.Require().Contains(w.Body.String(), expectedErrorMessage)
+	response := suite.decodeError(w.Body)
+	suite.Require().Contains(response.Message, expectedErrorMessage)
+	suite.Require().EqualValues(apierror.CodeValidation, response.Code)
 }
 
 func (suite *authHandlerSuite) TestRegisterSuccess() {
@@ -140,6 +134,60 @@ func (suite *authHandlerSuite) TestRegisterSuccess() {
 	suite.Greater(response.UserId, 0)
 }
 
+func (suite *authHandlerSuite) TestRegisterEmailTaken() {
+	body, err := json.Marshal(usecase.CreateUserRequest{Email: "user@example.com", Password: "validpassword", UserType: "client"})
+	suite.Require().NoError(err)
+
+	w := httptest.NewRecorder()
+	suite.handler.Register(w, httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(body)))
+	suite.Require().EqualValues(http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	suite.handler.Register(w, httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(body)))
+
+	suite.Require().EqualValues(http.StatusConflict, w.Code)
+	suite.Require().Empty(w.Header().Get("Retry-After"))
+	errResponse := suite.decodeError(w.Body)
+	suite.Require().EqualValues(apierror.CodeEmailTaken, errResponse.Code)
+}
+
+func (suite *authHandlerSuite) TestRegisterUserShape() {
+	response, err := suite.handler.RegisterUser(context.Background(), api.RegisterUserRequestObject{
+		Body: &api.RegisterUserJSONRequestBody{Email: "user@example.com", Password: "validpassword", UserType: api.Client},
+	})
+	suite.Require().NoError(err)
+
+	w := httptest.NewRecorder()
+	suite.Require().NoError(response.VisitRegisterUserResponse(w))
+	suite.Require().EqualValues(http.StatusOK, w.Code)
+
+	var body map[string]int
+	err = json.Unmarshal(w.Body.Bytes(), &body)
+	suite.Require().NoError(err)
+	suite.Require().NotContains(body, "id")
+	suite.Greater(body["user_id"], 0)
+}
+
+func (suite *authHandlerSuite) TestRegisterUserFailValidation() {
+	_, err := suite.handler.RegisterUser(context.Background(), api.RegisterUserRequestObject{
+		Body: &api.RegisterUserJSONRequestBody{Email: "user@example.com", Password: "short", UserType: api.Client},
+	})
+	suite.Require().Error(err)
+	suite.Require().EqualValues(http.StatusBadRequest, Errors.From(err).Status)
+}
+
+func (suite *authHandlerSuite) TestRegisterUserEmailTaken() {
+	request := api.RegisterUserRequestObject{
+		Body: &api.RegisterUserJSONRequestBody{Email: "user@example.com", Password: "validpassword", UserType: api.Client},
+	}
+	_, err := suite.handler.RegisterUser(context.Background(), request)
+	suite.Require().NoError(err)
+
+	_, err = suite.handler.RegisterUser(context.Background(), request)
+	suite.Require().ErrorIs(err, ErrEmailTaken)
+	suite.Require().EqualValues(http.StatusConflict, Errors.From(err).Status)
+}
+
 func (suite *authHandlerSuite) TestLoginFailDecoding() {
 	invalidBody := `{ "id": "not_a_number", "password": "somepassword" }`
 	invalidBodyBytes := []byte(invalidBody)
@@ -158,7 +206,7 @@ func (suite *authHandlerSuite) TestLoginFailDecoding() {
 
 func (suite *authHandlerSuite) TestLoginFailValidation() {
 	invalidPayload := `{"id": 0, "password": "validpassword"}`
-	expectedError := "Key: 'LoginRequest.ID' Error:Field validation for 'ID' failed on the 'required' tag\n"
+	expectedError := "Key: 'LoginRequest.ID' Error:Field validation for 'ID' failed on the 'required' tag"
 
 	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader([]byte(invalidPayload)))
 	req.Header.Set("Content-Type", "application/json")
@@ -168,8 +216,8 @@ func (suite *authHandlerSuite) TestLoginFailValidation() {
 
 	suite.Require().EqualValues(http.StatusBadRequest, w.Code)
 
-	body := w.Body.String()
-	suite.Require().Contains(body, expectedError)
+	response := suite.decodeError(w.Body)
+	suite.Require().Contains(response.Message, expectedError)
 }
 
 func (suite *authHandlerSuite) TestLoginUserNotFound() {
@@ -189,7 +237,9 @@ func (suite *authHandlerSuite) TestLoginUserNotFound() {
 
 	suite.Require().EqualValues(http.StatusNotFound, w.Code)
 
-	suite.Require().EqualValues("User not found\n", w.Body.String())
+	response := suite.decodeError(w.Body)
+	suite.Require().EqualValues("User not found", response.Message)
+	suite.Require().EqualValues(apierror.CodeUserNotFound, response.Code)
 }
 
 func (suite *authHandlerSuite) TestLoginInvalidPassword() {
@@ -228,35 +278,43 @@ func (suite *authHandlerSuite) TestLoginInvalidPassword() {
 	suite.handler.Login(w, req)
 
 	suite.Require().EqualValues(http.StatusUnauthorized, w.Code)
-	suite.Require().EqualValues("Invalid password\n", w.Body.String())
+	errResponse := suite.decodeError(w.Body)
+	suite.Require().EqualValues("Invalid password", errResponse.Message)
+	suite.Require().EqualValues(apierror.CodeInvalidPassword, errResponse.Code)
 }
 
-func (suite *authHandlerSuite) clearTestDB(db *postgres.Database) {
+func (suite *authHandlerSuite) TestSetUserRole() {
 	ctx := context.Background()
-	tables := []string{`"user"`}
-	_, err := db.Exec(ctx, "SET session_replication_role = 'replica'")
+	registered, err := suite.handler.RegisterUser(ctx, api.RegisterUserRequestObject{
+		Body: &api.RegisterUserJSONRequestBody{Email: "admin@example.com", Password: "password123", UserType: api.Moderator},
+	})
 	suite.Require().NoError(err)
+	userID := registered.(api.RegisterUser200JSONResponse).UserId
 
-	for _, table := range tables {
-		_, err := db.Exec(ctx, fmt.Sprintf("TRUNCATE %s CASCADE", table))
-		suite.Require().NoError(err)
-	}
-
-	_, err = db.Exec(ctx, "SET session_replication_role = 'origin'")
+	response, err := suite.handler.SetUserRole(ctx, api.SetUserRoleRequestObject{Id: userID, Body: &api.SetUserRoleJSONRequestBody{Role: usecase.RoleAdmin}})
 	suite.Require().NoError(err)
+	suite.Require().Contains(response.(api.SetUserRole200JSONResponse).Permissions, string(usecase.PermUserManage))
 
-	fmt.Println("Test database cleared successfully")
+	// The role takes effect with the next login
+	login, err := suite.handler.LoginUser(ctx, api.LoginUserRequestObject{Body: &api.LoginUserJSONRequestBody{Id: userID, Password: "password123"}})
+	suite.Require().NoError(err)
+	claims, err := suite.handler.tokens.Parse(login.(api.LoginUser200JSONResponse).Token)
+	suite.Require().NoError(err)
+	suite.Require().Equal(usecase.RoleAdmin, claims.Role)
+	suite.Require().True(claims.Can(usecase.PermUserManage))
+
+	_, err = suite.handler.SetUserRole(ctx, api.SetUserRoleRequestObject{Id: userID, Body: &api.SetUserRoleJSONRequestBody{Role: "owner"}})
+	suite.Require().ErrorIs(err, ErrUnknownRole)
+	_, err = suite.handler.SetUserRole(ctx, api.SetUserRoleRequestObject{Id: 42, Body: &api.SetUserRoleJSONRequestBody{Role: usecase.RoleClient}})
+	suite.Require().ErrorIs(err, ErrUserNotFound)
+	// An unknown role is reported before an unknown user, as in Postgres
+	_, err = suite.handler.SetUserRole(ctx, api.SetUserRoleRequestObject{Id: 42, Body: &api.SetUserRoleJSONRequestBody{Role: "owner"}})
+	suite.Require().ErrorIs(err, ErrUnknownRole)
 }
 
-func (suite *authHandlerSuite) fromEnv() postgres.DatabaseConfig {
-	err := godotenv.Load("../../../.env")
+func (suite *authHandlerSuite) decodeError(body io.Reader) apierror.Error {
+	var response apierror.Error
+	err := json.NewDecoder(body).Decode(&response)
 	suite.Require().NoError(err)
-
-	return postgres.DatabaseConfig{
-		Host:     os.Getenv("DB_HOST"),
-		Port:     os.Getenv("DB_PORT"),
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		Name:     os.Getenv("DB_NAME"),
-	}
+	return response
 }
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"slices"
+)
+
+var (
+	_ Authorizer  = (*MemoryRepo)(nil)
+	_ BatchReader = (*MemoryRepo)(nil)
+)
+
+// MemoryRepo is an Authorizer backed by memory.Store, used by tests that don't need PostgreSQL.
+type MemoryRepo struct {
+	store  *memory.Store
+	tokens *TokenManager
+}
+
+func NewMemoryRepo(store *memory.Store, tokens *TokenManager) *MemoryRepo {
+	return &MemoryRepo{
+		store:  store,
+		tokens: tokens,
+	}
+}
+
+func (repo *MemoryRepo) Register(ctx context.Context, login usecase.CreateUserRequest) (usecase.CreateUserResponse, error) {
+	hash, err := hashPassword(login.Password)
+	if err != nil {
+		return usecase.CreateUserResponse{}, err
+	}
+
+	repo.store.Lock()
+	defer repo.store.Unlock()
+
+	for _, user := range repo.store.Users {
+		if user.Email == login.Email {
+			return usecase.CreateUserResponse{}, ErrEmailTaken
+		}
+	}
+
+	id := repo.store.NextID("user")
+	repo.store.Users[id] = memory.User{
+		ID:           id,
+		Email:        login.Email,
+		PasswordHash: hash,
+		UserType:     login.UserType,
+	}
+
+	return usecase.CreateUserResponse{UserId: id}, nil
+}
+
+func (repo *MemoryRepo) Login(ctx context.Context, login usecase.LoginRequest) (usecase.LoginResponse, error) {
+	repo.store.RLock()
+	user, ok := repo.store.Users[login.ID]
+	repo.store.RUnlock()
+
+	if !ok {
+		return usecase.LoginResponse{}, ErrUserNotFound
+	}
+
+	if !checkPassword(user.PasswordHash, login.Password) {
+		return usecase.LoginResponse{}, ErrInvalidPassword
+	}
+
+	permissions, err := repo.Permissions(ctx, user.UserType)
+	if err != nil {
+		return usecase.LoginResponse{}, err
+	}
+
+	token, err := repo.tokens.Generate(login.ID, user.UserType, permissions)
+	if err != nil {
+		return usecase.LoginResponse{}, err
+	}
+
+	return usecase.LoginResponse{Token: token}, nil
+}
+
+func (repo *MemoryRepo) UsersByID(ctx context.Context, ids []int) ([]usecase.User, error) {
+	repo.store.RLock()
+	defer repo.store.RUnlock()
+
+	users := make([]usecase.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := repo.store.Users[id]; ok {
+			users = append(users, usecase.User{ID: user.ID, Email: user.Email, UserType: user.UserType})
+		}
+	}
+
+	return users, nil
+}
+
+func (repo *MemoryRepo) Permissions(ctx context.Context, role string) ([]usecase.Permission, error) {
+	repo.store.RLock()
+	defer repo.store.RUnlock()
+
+	return slices.Clone(repo.store.Roles[role]), nil
+}
+
+func (repo *MemoryRepo) SetUserType(ctx context.Context, id int, userType string) error {
+	repo.store.Lock()
+	defer repo.store.Unlock()
+
+	// The role is checked first, like the foreign key of Repo.SetUserType
+	if _, ok := repo.store.Roles[userType]; !ok {
+		return ErrUnknownRole
+	}
+	user, ok := repo.store.Users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	user.UserType = userType
+	repo.store.Users[id] = user
+	return nil
+}
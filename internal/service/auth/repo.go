@@ -6,18 +6,30 @@ import (
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var _ Authorizer = (*Repo)(nil)
+var (
+	_ Authorizer  = (*Repo)(nil)
+	_ BatchReader = (*Repo)(nil)
+)
+
+// BatchReader loads many users at once, so that GraphQL resolvers can batch
+// their lookups.
+type BatchReader interface {
+	UsersByID(ctx context.Context, ids []int) ([]usecase.User, error)
+}
 
 type Repo struct {
-	db *postgres.Database
+	db     *postgres.Database
+	tokens *TokenManager
 }
 
-func NewRepo(db *postgres.Database) *Repo {
+func NewRepo(db *postgres.Database, tokens *TokenManager) *Repo {
 	return &Repo{
-		db: db,
+		db:     db,
+		tokens: tokens,
 	}
 }
 
@@ -32,6 +44,10 @@ func (repo *Repo) Register(ctx context.Context, login usecase.CreateUserRequest)
 	err = repo.db.ExecQueryRow(ctx, query, login.Email, hash, login.UserType).Scan(&response.UserId)
 
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return response, ErrEmailTaken
+		}
 		return response, err
 	}
 
@@ -44,8 +60,9 @@ func (repo *Repo) Login(ctx context.Context, login usecase.LoginRequest) (usecas
 		PasswordHash string `db:"password_hash"`
 	}
 
+	// Логин часто следует сразу за регистрацией, реплика может ещё не получить пользователя
 	query := `SELECT password_hash, user_type FROM "user" WHERE id = $1`
-	err := repo.db.Get(ctx, &response, query, login.ID)
+	err := repo.db.Get(postgres.WithPrimary(ctx), &response, query, login.ID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return usecase.LoginResponse{}, ErrUserNotFound
@@ -58,7 +75,12 @@ func (repo *Repo) Login(ctx context.Context, login usecase.LoginRequest) (usecas
 		return usecase.LoginResponse{}, ErrInvalidPassword
 	}
 
-	token, err := GenerateToken(login.ID, response.UserType)
+	permissions, err := repo.Permissions(ctx, response.UserType)
+	if err != nil {
+		return usecase.LoginResponse{}, err
+	}
+
+	token, err := repo.tokens.Generate(login.ID, response.UserType, permissions)
 	if err != nil {
 		return usecase.LoginResponse{}, err
 	}
@@ -66,6 +88,73 @@ func (repo *Repo) Login(ctx context.Context, login usecase.LoginRequest) (usecas
 	return usecase.LoginResponse{Token: token}, nil
 }
 
+func (repo *Repo) Permissions(ctx context.Context, role string) ([]usecase.Permission, error) {
+	query := `SELECT permission FROM role_permission WHERE role = $1 ORDER BY permission`
+
+	var permissions []usecase.Permission
+	err := repo.db.Select(ctx, &permissions, query, role)
+	return permissions, err
+}
+
+// Roles returns every role with its permissions.
+func (repo *Repo) Roles(ctx context.Context) ([]usecase.Role, error) {
+	query := `SELECT r.name, COALESCE(array_agg(p.permission ORDER BY p.permission) FILTER (WHERE p.permission IS NOT NULL), '{}') AS permissions
+		FROM role r LEFT JOIN role_permission p ON p.role = r.name
+		GROUP BY r.name ORDER BY r.name`
+
+	var roles []usecase.Role
+	err := repo.db.Select(ctx, &roles, query)
+	return roles, err
+}
+
+// UsersByID returns the users with the given ids, missing ids are skipped.
+func (repo *Repo) UsersByID(ctx context.Context, ids []int) ([]usecase.User, error) {
+	query := `SELECT id, email, user_type FROM "user" WHERE id = ANY($1)`
+
+	users := make([]usecase.User, 0, len(ids))
+	err := repo.db.Select(ctx, &users, query, ids)
+	return users, err
+}
+
+// SetUserType changes the role of a user, tokens issued before keep the old
+// role and permissions until they expire.
+func (repo *Repo) SetUserType(ctx context.Context, id int, userType string) error {
+	query := `UPDATE "user" SET user_type = $2 WHERE id = $1`
+
+	tag, err := repo.db.Exec(ctx, query, id, userType)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrUnknownRole
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (repo *Repo) ResetPassword(ctx context.Context, request usecase.PasswordResetRequest) error {
+	hash, err := hashPassword(request.Password)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE "user" SET password_hash = $2 WHERE id = $1`
+
+	tag, err := repo.db.Exec(ctx, query, request.ID, hash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
 func hashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"github.com/joho/godotenv"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
@@ -25,9 +26,13 @@ func TestAuthTestSuite(t *testing.T) {
 func (suite *authRepoSuite) SetupSuite() {
 	db, err := postgres.NewDB(context.Background(), suite.fromEnv())
 	suite.Require().NoError(err)
+	if err = db.GetPool().Ping(context.Background()); err != nil {
+		db.Close()
+		suite.T().Skipf("PostgreSQL is not available: %v", err)
+	}
 
 	suite.db = db
-	suite.repo = NewRepo(suite.db)
+	suite.repo = NewRepo(suite.db, NewTokenManager(Config{Secret: "test-secret", TokenTTL: time.Hour, Issuer: "test"}))
 }
 
 func (suite *authRepoSuite) TearDownSuite() {
@@ -112,6 +117,54 @@ func (suite *authRepoSuite) TestLoginInvalidPassword() {
 	suite.Require().ErrorIs(err, ErrInvalidPassword)
 }
 
+func (suite *authRepoSuite) TestSetUserTypeAndResetPassword() {
+	ctx := context.Background()
+
+	response, err := suite.repo.Register(ctx, usecase.CreateUserRequest{
+		Email:    "testadmin@example.com",
+		Password: "password123",
+		UserType: "client",
+	})
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.repo.SetUserType(ctx, response.UserId, "moderator"))
+	suite.Require().NoError(suite.repo.ResetPassword(ctx, usecase.PasswordResetRequest{ID: response.UserId, Password: "newpassword"}))
+
+	users, err := suite.repo.UsersByID(postgres.WithPrimary(ctx), []int{response.UserId})
+	suite.Require().NoError(err)
+	suite.Require().Len(users, 1)
+	suite.Require().Equal("moderator", users[0].UserType)
+
+	_, err = suite.repo.Login(ctx, usecase.LoginRequest{ID: response.UserId, Password: "password123"})
+	suite.Require().ErrorIs(err, ErrInvalidPassword)
+	_, err = suite.repo.Login(ctx, usecase.LoginRequest{ID: response.UserId, Password: "newpassword"})
+	suite.Require().NoError(err)
+
+	suite.Require().ErrorIs(suite.repo.SetUserType(ctx, 99999999, "moderator"), ErrUserNotFound)
+	suite.Require().ErrorIs(suite.repo.SetUserType(ctx, response.UserId, "owner"), ErrUnknownRole)
+	suite.Require().ErrorIs(suite.repo.SetUserType(ctx, 99999999, "owner"), ErrUnknownRole)
+	suite.Require().ErrorIs(suite.repo.ResetPassword(ctx, usecase.PasswordResetRequest{ID: 99999999, Password: "newpassword"}), ErrUserNotFound)
+}
+
+func (suite *authRepoSuite) TestRolesMatchDefaults() {
+	ctx := context.Background()
+
+	roles, err := suite.repo.Roles(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(roles, len(usecase.DefaultRoles))
+	for _, role := range roles {
+		suite.Require().ElementsMatch(usecase.DefaultRoles[role.Name], role.Permissions, role.Name)
+
+		permissions, err := suite.repo.Permissions(ctx, role.Name)
+		suite.Require().NoError(err)
+		suite.Require().ElementsMatch(role.Permissions, permissions)
+	}
+
+	permissions, err := suite.repo.Permissions(ctx, "owner")
+	suite.Require().NoError(err)
+	suite.Require().Empty(permissions)
+}
+
 func (suite *authRepoSuite) clearTestDB(db *postgres.Database) {
 	ctx := context.Background()
 	tables := []string{`"user"`}
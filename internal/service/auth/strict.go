@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+// The methods below implement the auth part of api.StrictServerInterface for /v2.
+
+func (auth *Handler) IssueDummyToken(ctx context.Context, request api.IssueDummyTokenRequestObject) (api.IssueDummyTokenResponseObject, error) {
+	token, err := auth.dummyToken(ctx, string(request.Params.UserType))
+	if err != nil {
+		return nil, err
+	}
+
+	return api.IssueDummyToken200JSONResponse{Token: token}, nil
+}
+
+func (auth *Handler) RegisterUser(ctx context.Context, request api.RegisterUserRequestObject) (api.RegisterUserResponseObject, error) {
+	req := usecase.CreateUserRequest{
+		Email:    string(request.Body.Email),
+		Password: request.Body.Password,
+		UserType: string(request.Body.UserType),
+	}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	response, err := auth.repo.Register(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.RegisterUser200JSONResponse{UserId: response.UserId}, nil
+}
+
+func (auth *Handler) LoginUser(ctx context.Context, request api.LoginUserRequestObject) (api.LoginUserResponseObject, error) {
+	req := usecase.LoginRequest{ID: request.Body.Id, Password: request.Body.Password}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	response, err := auth.repo.Login(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.LoginUser200JSONResponse{Token: response.Token}, nil
+}
+
+func (auth *Handler) SetUserRole(ctx context.Context, request api.SetUserRoleRequestObject) (api.SetUserRoleResponseObject, error) {
+	req := usecase.SetRoleRequest{UserID: request.Id, Role: request.Body.Role}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	if err := auth.repo.SetUserType(ctx, req.UserID, req.Role); err != nil {
+		return nil, err
+	}
+
+	permissions, err := auth.repo.Permissions(ctx, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	response := api.SetUserRole200JSONResponse{Id: req.UserID, Role: req.Role, Permissions: make([]api.Permission, 0, len(permissions))}
+	for _, permission := range permissions {
+		response.Permissions = append(response.Permissions, string(permission))
+	}
+
+	return response, nil
+}
@@ -1,46 +1,114 @@
 package auth
 
 import (
-	"os"
+	"crypto/rand"
+	"encoding/base64"
+	"slices"
 	"time"
 
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
 	"github.com/dgrijalva/jwt-go"
 )
 
-const (
-	jwtSecret = "JWT_SECRET"
-	myApp     = "my-app"
-)
+const secretSize = 32
+
+type Config struct {
+	Secret string `yaml:"secret" validate:"required"`
+	// PreviousSecrets are retired signing keys, tokens signed with them are
+	// still accepted until they expire.
+	PreviousSecrets []string      `yaml:"previous_secrets" validate:"dive,required"`
+	TokenTTL        time.Duration `yaml:"token_ttl" validate:"gt=0"`
+	Issuer          string        `yaml:"issuer" validate:"required"`
+}
+
+// Rotate returns the config with a new random secret. The current secret is
+// retired to PreviousSecrets and older ones are dropped, so rotations must be
+// at least TokenTTL apart.
+func (config Config) Rotate() (Config, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return config, err
+	}
+
+	config.PreviousSecrets = []string{config.Secret}
+	config.Secret = base64.RawURLEncoding.EncodeToString(secret)
+	return config, nil
+}
 
 type Claims struct {
-	UserID int    `json:"user_id"`
-	Role   string `json:"role"`
+	UserID      int                  `json:"user_id"`
+	Role        string               `json:"role"`
+	Permissions []usecase.Permission `json:"permissions"`
 	jwt.StandardClaims
 }
 
-func GenerateToken(userID int, role string) (string, error) {
-	secret := []byte(os.Getenv(jwtSecret))
+// Can reports whether the token grants permission.
+func (c *Claims) Can(permission usecase.Permission) bool {
+	return slices.Contains(c.Permissions, permission)
+}
+
+// TokenManager issues and verifies the HS256 tokens handed out by /login and /dummyLogin.
+// Tokens are signed with the current secret and verified with it or any of
+// the previous ones.
+type TokenManager struct {
+	secrets [][]byte
+	ttl     time.Duration
+	issuer  string
+}
+
+func NewTokenManager(config Config) *TokenManager {
+	secrets := [][]byte{[]byte(config.Secret)}
+	for _, secret := range config.PreviousSecrets {
+		secrets = append(secrets, []byte(secret))
+	}
+
+	return &TokenManager{
+		secrets: secrets,
+		ttl:     config.TokenTTL,
+		issuer:  config.Issuer,
+	}
+}
+
+// Generate issues a token for the user with the permissions of role, which
+// are resolved by the caller so that they come from the database.
+func (manager *TokenManager) Generate(userID int, role string, permissions []usecase.Permission) (string, error) {
+	// An empty list must not be mistaken for a token issued before permissions
+	if permissions == nil {
+		permissions = []usecase.Permission{}
+	}
+
 	claims := Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:      userID,
+		Role:        role,
+		Permissions: permissions,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
-			Issuer:    myApp,
+			ExpiresAt: time.Now().Add(manager.ttl).Unix(),
+			Issuer:    manager.issuer,
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secret)
+	return token.SignedString(manager.secrets[0])
 }
 
-func ParseToken(tokenString string) (*Claims, error) {
-	secret := []byte(os.Getenv(jwtSecret))
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
+func (manager *TokenManager) Parse(tokenString string) (*Claims, error) {
+	var (
+		token *jwt.Token
+		err   error
+	)
+	for _, secret := range manager.secrets {
+		token, err = jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, jwt.ErrSignatureInvalid
+			}
+			return secret, nil
+		})
+
+		// Only a signature mismatch means the token may be signed with another secret
+		if ve, ok := err.(*jwt.ValidationError); !ok || ve.Errors&jwt.ValidationErrorSignatureInvalid == 0 {
+			break
 		}
-		return secret, nil
-	})
+	}
 
 	if err != nil {
 		if ve, ok := err.(*jwt.ValidationError); ok {
@@ -61,5 +129,10 @@ func ParseToken(tokenString string) (*Claims, error) {
 		return nil, ErrTokenInvalid
 	}
 
+	// Tokens issued before permissions were added carry only the role
+	if claims.Permissions == nil {
+		claims.Permissions = usecase.DefaultRoles[claims.Role]
+	}
+
 	return claims, nil
 }
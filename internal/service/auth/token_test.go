@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRotatedSecrets(t *testing.T) {
+	config := Config{Secret: "old-secret", TokenTTL: time.Hour, Issuer: "test"}
+	old := NewTokenManager(config)
+	token, err := old.Generate(1, "client", nil)
+	require.NoError(t, err)
+
+	rotated, err := config.Rotate()
+	require.NoError(t, err)
+	require.NotEqual(t, config.Secret, rotated.Secret)
+	require.Equal(t, []string{config.Secret}, rotated.PreviousSecrets)
+
+	// Tokens signed before the rotation stay valid, new ones use the new secret
+	current := NewTokenManager(rotated)
+	claims, err := current.Parse(token)
+	require.NoError(t, err)
+	require.Equal(t, 1, claims.UserID)
+
+	token, err = current.Generate(2, "moderator", nil)
+	require.NoError(t, err)
+	_, err = old.Parse(token)
+	require.Equal(t, ErrTokenInvalid, err)
+
+	// The second rotation drops the original secret
+	rotated, err = rotated.Rotate()
+	require.NoError(t, err)
+	_, err = NewTokenManager(rotated).Parse(token)
+	require.NoError(t, err)
+
+	token, err = old.Generate(1, "client", nil)
+	require.NoError(t, err)
+	_, err = NewTokenManager(rotated).Parse(token)
+	require.Equal(t, ErrTokenInvalid, err)
+}
+
+func TestExpiredTokenWithPreviousSecret(t *testing.T) {
+	config := Config{Secret: "old-secret", TokenTTL: -time.Minute, Issuer: "test"}
+	token, err := NewTokenManager(config).Generate(1, "client", nil)
+	require.NoError(t, err)
+
+	rotated, err := config.Rotate()
+	require.NoError(t, err)
+	_, err = NewTokenManager(rotated).Parse(token)
+	require.Equal(t, ErrTokenExpired, err)
+}
+
+func TestClaimsPermissions(t *testing.T) {
+	tokens := NewTokenManager(Config{Secret: "test-secret", TokenTTL: time.Hour, Issuer: "test"})
+
+	token, err := tokens.Generate(1, "auditor", []usecase.Permission{usecase.PermDataExport})
+	require.NoError(t, err)
+	claims, err := tokens.Parse(token)
+	require.NoError(t, err)
+	require.True(t, claims.Can(usecase.PermDataExport))
+	require.False(t, claims.Can(usecase.PermHouseRead))
+
+	// A role without permissions is not mistaken for a legacy token
+	token, err = tokens.Generate(2, usecase.RoleClient, nil)
+	require.NoError(t, err)
+	claims, err = tokens.Parse(token)
+	require.NoError(t, err)
+	require.False(t, claims.Can(usecase.PermHouseRead))
+
+	// Tokens issued before permissions were added fall back to the defaults of their role
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 3,
+		"role":    usecase.RoleModerator,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	token, err = legacy.SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+	claims, err = tokens.Parse(token)
+	require.NoError(t, err)
+	require.True(t, claims.Can(usecase.PermFlatModerate))
+	require.False(t, claims.Can(usecase.PermUserManage))
+}
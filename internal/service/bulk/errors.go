@@ -0,0 +1,29 @@
+package bulk
+
+import (
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"net/http"
+	"slices"
+)
+
+var (
+	// ErrRolledBack is reported for the valid rows of a chunk in which another row failed.
+	ErrRolledBack = errors.New("row was rolled back because another row of its chunk failed")
+	// ErrAborted is reported for the rows left when an internal error stops an
+	// import after some of its chunks were committed.
+	ErrAborted = errors.New("row was not imported because the import was aborted")
+)
+
+// Errors maps the errors of the package, and of the house and flat
+// repositories rows are imported with, to their API representation.
+var Errors = slices.Concat(house.Errors, flat.Errors, apierror.Mapper{
+	apierror.Map(ErrRolledBack, http.StatusConflict, apierror.CodeImportRolledBack, "Row was rolled back because another row of its chunk failed"),
+	apierror.Map(ErrAborted, http.StatusInternalServerError, apierror.CodeImportAborted, "Row was not imported because the import was aborted"),
+})
+
+func invalidFile(err error) error {
+	return apierror.BadRequest(apierror.CodeInvalidImport, "Invalid import file: "+err.Error())
+}
@@ -0,0 +1,30 @@
+package bulk
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+type Importer interface {
+	Import(ctx context.Context, rows []usecase.ImportRow, options usecase.ImportOptions) (usecase.ImportReport, error)
+}
+
+type Config struct {
+	// MaxBytes is the largest import file accepted.
+	MaxBytes int `yaml:"max_bytes" validate:"gt=0"`
+}
+
+type Handler struct {
+	repo   Importer
+	config Config
+}
+
+func NewHandler(repo Importer, config Config) *Handler {
+	return &Handler{repo: repo, config: config}
+}
+
+// MaxBytes is the largest import file the handler accepts, the router limits
+// the body to it before the request is read.
+func (h *Handler) MaxBytes() int64 {
+	return int64(h.config.MaxBytes)
+}
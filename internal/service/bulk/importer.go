@@ -0,0 +1,199 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"maps"
+	"net/http"
+)
+
+var (
+	errChunkFailed = errors.New("chunk has failed rows")
+	errDryRun      = errors.New("dry run")
+)
+
+// store is what importRows needs from a repository.
+type store interface {
+	createHouse(ctx context.Context, request usecase.HouseCreateRequest) (usecase.House, error)
+	createFlat(ctx context.Context, request usecase.FlatCreateRequest) (usecase.FlatResponse, error)
+	// atomic runs fn in a transaction, or in a savepoint of the one in ctx, and
+	// discards the changes of fn if it fails.
+	atomic(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// importRows imports the rows chunk by chunk. A chunk is committed only if all
+// of its rows succeed, still every row is tried so that the report lists all
+// the errors. Errors of rows that clients can fix are reported per row, the
+// others abort the import: the committed chunks are then reported as imported
+// and the rows left as aborted, so that clients don't import them again. A dry
+// run imports everything in one transaction and rolls it back.
+func importRows(ctx context.Context, s store, rows []usecase.ImportRow, options usecase.ImportOptions) (usecase.ImportReport, error) {
+	size := options.ChunkSize
+	if size == 0 {
+		size = len(rows)
+	}
+
+	var (
+		results []usecase.ImportRowResult
+		refs    map[string]int
+		aborted bool
+	)
+	run := func(ctx context.Context) error {
+		// A retried transaction starts over
+		results = make([]usecase.ImportRowResult, 0, len(rows))
+		refs = make(map[string]int)
+
+		for start := 0; start < len(rows); start += size {
+			chunk, err := importChunk(ctx, s, rows[start:min(start+size, len(rows))], refs)
+			if err != nil {
+				// Nothing is committed yet, the import can simply be retried
+				if options.DryRun || start == 0 {
+					return err
+				}
+
+				logger.FromContext(ctx).Error("Import aborted", "error", err, "line", rows[start].Line)
+				aborted = true
+				for _, row := range rows[start:] {
+					results = append(results, usecase.ImportRowResult{Line: row.Line, Kind: row.Kind, Err: ErrAborted})
+				}
+				return nil
+			}
+			results = append(results, chunk...)
+		}
+		return nil
+	}
+
+	var err error
+	if options.DryRun {
+		err = s.atomic(ctx, func(ctx context.Context) error {
+			if err := run(ctx); err != nil {
+				return err
+			}
+			return errDryRun
+		})
+		if errors.Is(err, errDryRun) {
+			err = nil
+		}
+	} else {
+		err = run(ctx)
+	}
+	if err != nil {
+		return usecase.ImportReport{}, err
+	}
+
+	report := usecase.ImportReport{Total: len(rows), DryRun: options.DryRun, Aborted: aborted, Rows: results}
+	for i := range report.Rows {
+		if report.Rows[i].Err != nil {
+			report.Failed++
+			continue
+		}
+		report.Imported++
+		if options.DryRun {
+			report.Rows[i].ID = 0
+		}
+	}
+	return report, nil
+}
+
+// importChunk imports the rows in one transaction and adds the refs of the
+// imported houses to refs once it is committed.
+func importChunk(ctx context.Context, s store, rows []usecase.ImportRow, refs map[string]int) ([]usecase.ImportRowResult, error) {
+	var (
+		results []usecase.ImportRowResult
+		added   map[string]int
+	)
+	err := s.atomic(ctx, func(ctx context.Context) error {
+		results = make([]usecase.ImportRowResult, 0, len(rows))
+		added = make(map[string]int)
+
+		failed := false
+		for _, row := range rows {
+			result := usecase.ImportRowResult{Line: row.Line, Kind: row.Kind}
+			result.ID, result.Err = importRow(ctx, s, row, refs, added)
+			if result.Err != nil {
+				if Errors.From(result.Err).Status >= http.StatusInternalServerError {
+					return result.Err
+				}
+				failed = true
+			}
+			results = append(results, result)
+		}
+
+		if failed {
+			return errChunkFailed
+		}
+		return nil
+	})
+
+	if errors.Is(err, errChunkFailed) {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].ID, results[i].Err = 0, ErrRolledBack
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	maps.Copy(refs, added)
+	return results, nil
+}
+
+// importRow imports a row in a savepoint, so that a failed row leaves the
+// transaction of its chunk usable. refs are the houses of the committed chunks,
+// added those of the current one.
+func importRow(ctx context.Context, s store, row usecase.ImportRow, refs, added map[string]int) (int, error) {
+	if err := row.Validate(); err != nil {
+		return 0, apierror.Validation(err)
+	}
+
+	lookup := func(ref string) (int, bool) {
+		if id, ok := added[ref]; ok {
+			return id, true
+		}
+		id, ok := refs[ref]
+		return id, ok
+	}
+
+	var id int
+	err := s.atomic(ctx, func(ctx context.Context) error {
+		switch row.Kind {
+		case "house":
+			if _, ok := lookup(row.Ref); row.Ref != "" && ok {
+				return apierror.Validation(fmt.Errorf("ref %q is already used by another house", row.Ref))
+			}
+
+			house, err := s.createHouse(ctx, row.House())
+			if err != nil {
+				return err
+			}
+			if row.Ref != "" {
+				added[row.Ref] = house.ID
+			}
+			id = house.ID
+		default:
+			request := row.Flat()
+			if row.HouseRef != "" {
+				houseID, ok := lookup(row.HouseRef)
+				if !ok {
+					return apierror.Validation(fmt.Errorf("house_ref %q does not match a house imported before", row.HouseRef))
+				}
+				request.HouseID = houseID
+			}
+
+			flat, err := s.createFlat(ctx, request)
+			if err != nil {
+				return err
+			}
+			id = flat.ID
+		}
+		return nil
+	})
+	return id, err
+}
@@ -0,0 +1,140 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const importCSV = `kind,ref,address,year,developer,house_id,house_ref,number,price,rooms
+house,A,"Лесная улица, 7, Москва",2000,Мэрия города,,,,,
+flat,,,,,,A,1,10000,2
+flat,,,,,,A,2,20000,3
+house,B,"Невский проспект, 1, Санкт-Петербург",1900,,,,,,
+flat,,,,,,B,1,30000,abc
+flat,,,,,,B,1,30000,4
+`
+
+func TestParse(t *testing.T) {
+	rows, err := Parse(strings.NewReader(importCSV), CSV)
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+	require.Equal(t, usecase.ImportRow{Line: 2, Kind: "house", Ref: "A", Address: "Лесная улица, 7, Москва", Year: 2000, Developer: "Мэрия города"}, rows[0])
+	require.Equal(t, usecase.ImportRow{Line: 3, Kind: "flat", HouseRef: "A", Number: 1, Price: 10000, Rooms: 2}, rows[1])
+	require.Equal(t, "rooms must be an integer", rows[4].Malformed)
+
+	rows, err = Parse(strings.NewReader(`{"kind": "flat", "house_id": 1, "number": 1, "price": 10000, "rooms": 2}
+
+{"kind": "flat", "floor": 2}
+`), NDJSON)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, usecase.ImportRow{Line: 1, Kind: "flat", HouseID: 1, Number: 1, Price: 10000, Rooms: 2}, rows[0])
+	require.Equal(t, 3, rows[1].Line)
+	require.Contains(t, rows[1].Malformed, "unknown field")
+
+	for _, body := range []string{"", "kind,floor\nflat,2\n", "ref\nA\n"} {
+		_, err = Parse(strings.NewReader(body), CSV)
+		require.Equal(t, apierror.CodeInvalidImport, Errors.From(err).Code, body)
+	}
+}
+
+var errConnection = errors.New("connection reset")
+
+// failingStore fails to create the house with address as the database would.
+type failingStore struct {
+	*MemoryRepo
+	address string
+}
+
+func (s failingStore) createHouse(ctx context.Context, request usecase.HouseCreateRequest) (usecase.House, error) {
+	if request.Address == s.address {
+		return usecase.House{}, errConnection
+	}
+	return s.MemoryRepo.createHouse(ctx, request)
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	rows, err := Parse(strings.NewReader(importCSV), CSV)
+	require.NoError(t, err)
+
+	t.Run("all or nothing", func(t *testing.T) {
+		store := memory.NewStore()
+		report, err := NewMemoryRepo(store).Import(ctx, rows, usecase.ImportOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 6, report.Total)
+		require.Equal(t, 0, report.Imported)
+		require.Equal(t, 6, report.Failed)
+		require.Equal(t, apierror.CodeValidation, Errors.From(report.Rows[4].Err).Code)
+		require.ErrorIs(t, report.Rows[0].Err, ErrRolledBack)
+		require.Empty(t, store.Houses)
+		require.Empty(t, store.Flats)
+	})
+
+	t.Run("chunks", func(t *testing.T) {
+		store := memory.NewStore()
+		report, err := NewMemoryRepo(store).Import(ctx, rows, usecase.ImportOptions{ChunkSize: 3})
+		require.NoError(t, err)
+		require.Equal(t, 3, report.Imported)
+		require.Equal(t, 3, report.Failed)
+		require.Equal(t, []int{1, 1, 2}, []int{report.Rows[0].ID, report.Rows[1].ID, report.Rows[2].ID})
+		require.ErrorIs(t, report.Rows[3].Err, ErrRolledBack)
+
+		// The malformed row rolls back the valid ones of the second chunk
+		require.ErrorIs(t, report.Rows[5].Err, ErrRolledBack)
+		require.Len(t, store.Houses, 1)
+		require.Len(t, store.Flats, 2)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		store := memory.NewStore()
+		valid := append(rows[:4:4], usecase.ImportRow{Line: 7, Kind: "flat", HouseRef: "B", Number: 2, Price: 30000, Rooms: 4})
+		valid = append(valid, usecase.ImportRow{Line: 8, Kind: "flat", HouseRef: "B", Number: 2, Price: 30000, Rooms: 4})
+
+		report, err := NewMemoryRepo(store).Import(ctx, valid, usecase.ImportOptions{DryRun: true, ChunkSize: 2})
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Equal(t, 4, report.Imported)
+		require.Equal(t, 2, report.Failed)
+		for _, row := range report.Rows {
+			require.Zero(t, row.ID)
+		}
+		require.ErrorIs(t, report.Rows[4].Err, ErrRolledBack)
+		require.ErrorIs(t, report.Rows[5].Err, flat.ErrDuplicateFlat)
+		require.Empty(t, store.Houses)
+		require.Empty(t, store.Flats)
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		store := memory.NewStore()
+		s := failingStore{MemoryRepo: NewMemoryRepo(store), address: "Невский проспект, 1, Санкт-Петербург"}
+		report, err := importRows(ctx, s, rows[:4], usecase.ImportOptions{ChunkSize: 3})
+		require.NoError(t, err)
+		require.True(t, report.Aborted)
+		require.Equal(t, 3, report.Imported)
+		require.Equal(t, 1, report.Failed)
+		require.ErrorIs(t, report.Rows[3].Err, ErrAborted)
+		require.Len(t, store.Houses, 1)
+
+		// Nothing is committed when the first chunk fails
+		_, err = importRows(ctx, s, rows[3:4], usecase.ImportOptions{ChunkSize: 3})
+		require.ErrorIs(t, err, errConnection)
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		report, err := NewMemoryRepo(memory.NewStore()).Import(ctx, []usecase.ImportRow{
+			{Line: 2, Kind: "flat", HouseRef: "A", Number: 1, Price: 10000, Rooms: 2},
+			{Line: 3, Kind: "house", Ref: "A", Address: "Лесная улица, 7", Year: 2000},
+		}, usecase.ImportOptions{ChunkSize: 1})
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Imported)
+		require.Contains(t, report.Rows[0].Err.Error(), `house_ref "A" does not match`)
+	})
+}
@@ -0,0 +1,44 @@
+package bulk
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+var _ Importer = (*MemoryRepo)(nil)
+
+// MemoryRepo is an Importer backed by memory.Store, used by tests that don't need PostgreSQL.
+type MemoryRepo struct {
+	store  *memory.Store
+	houses *house.MemoryRepo
+	flats  *flat.MemoryRepo
+}
+
+func NewMemoryRepo(store *memory.Store) *MemoryRepo {
+	return &MemoryRepo{
+		store:  store,
+		houses: house.NewMemoryRepo(store),
+		flats:  flat.NewMemoryRepo(store),
+	}
+}
+
+func (repo *MemoryRepo) Import(ctx context.Context, rows []usecase.ImportRow, options usecase.ImportOptions) (usecase.ImportReport, error) {
+	return importRows(ctx, repo, rows, options)
+}
+
+func (repo *MemoryRepo) createHouse(ctx context.Context, request usecase.HouseCreateRequest) (usecase.House, error) {
+	return repo.houses.Create(ctx, request)
+}
+
+func (repo *MemoryRepo) createFlat(ctx context.Context, request usecase.FlatCreateRequest) (usecase.FlatResponse, error) {
+	return repo.flats.Create(ctx, request)
+}
+
+func (repo *MemoryRepo) atomic(ctx context.Context, fn func(ctx context.Context) error) error {
+	return repo.store.Atomic(func() error {
+		return fn(ctx)
+	})
+}
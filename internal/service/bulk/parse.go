@@ -0,0 +1,166 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Formats of an import file.
+const (
+	CSV    = "csv"
+	NDJSON = "ndjson"
+)
+
+const maxLineSize = 1 << 20
+
+var errTooManyRows = fmt.Errorf("more than %d rows", usecase.MaxImportRows)
+
+// Parse reads the rows of an import file. A line that cannot be parsed becomes
+// a row with Malformed set, so that it is reported along with the others; only
+// an unreadable file is an error. A file over the limit of http.MaxBytesReader
+// is returned as is, see apierror.ErrTooLarge.
+func Parse(r io.Reader, format string) ([]usecase.ImportRow, error) {
+	var (
+		rows []usecase.ImportRow
+		err  error
+	)
+	switch format {
+	case CSV:
+		rows, err = parseCSV(r)
+	case NDJSON:
+		rows, err = parseNDJSON(r)
+	default:
+		err = fmt.Errorf("unknown format %q", format)
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, invalidFile(err)
+	}
+	if len(rows) == 0 {
+		return nil, invalidFile(errors.New("no rows"))
+	}
+	return rows, nil
+}
+
+// csvFields maps the CSV columns to the fields of row, they are named like the
+// NDJSON ones.
+func csvFields(row *usecase.ImportRow) map[string]any {
+	return map[string]any{
+		"kind":      &row.Kind,
+		"ref":       &row.Ref,
+		"address":   &row.Address,
+		"year":      &row.Year,
+		"developer": &row.Developer,
+		"house_id":  &row.HouseID,
+		"house_ref": &row.HouseRef,
+		"number":    &row.Number,
+		"price":     &row.Price,
+		"rooms":     &row.Rooms,
+	}
+}
+
+func parseCSV(r io.Reader) ([]usecase.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("no header")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	known := csvFields(&usecase.ImportRow{})
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if _, ok := known[column]; !ok {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("duplicate column %q", column)
+		}
+		seen[column] = true
+		header[i] = column
+	}
+	if !seen["kind"] {
+		return nil, errors.New("no kind column")
+	}
+
+	var rows []usecase.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, err
+		}
+		if len(rows) == usecase.MaxImportRows {
+			return nil, errTooManyRows
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := usecase.ImportRow{Line: line}
+		if err != nil {
+			row.Malformed = fmt.Sprintf("expected %d fields, got %d", len(header), len(record))
+			rows = append(rows, row)
+			continue
+		}
+
+		fields := csvFields(&row)
+		for i, value := range record {
+			switch field := fields[header[i]].(type) {
+			case *string:
+				*field = value
+			case *int:
+				if value == "" {
+					continue
+				}
+				if *field, err = strconv.Atoi(value); err != nil && row.Malformed == "" {
+					row.Malformed = fmt.Sprintf("%s must be an integer", header[i])
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func parseNDJSON(r io.Reader) ([]usecase.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+
+	var rows []usecase.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if len(rows) == usecase.MaxImportRows {
+			return nil, errTooManyRows
+		}
+
+		var row usecase.ImportRow
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			row = usecase.ImportRow{Malformed: "invalid JSON: " + err.Error()}
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
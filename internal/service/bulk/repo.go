@@ -0,0 +1,46 @@
+package bulk
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ Importer = (*Repo)(nil)
+
+// Repo imports through the house and flat repositories, which join the
+// transactions it opens.
+type Repo struct {
+	db     *postgres.Database
+	houses *house.Repo
+	flats  *flat.Repo
+}
+
+func NewRepo(db *postgres.Database) *Repo {
+	return &Repo{
+		db:     db,
+		houses: house.NewRepo(db),
+		flats:  flat.NewRepo(db),
+	}
+}
+
+func (repo *Repo) Import(ctx context.Context, rows []usecase.ImportRow, options usecase.ImportOptions) (usecase.ImportReport, error) {
+	return importRows(ctx, repo, rows, options)
+}
+
+func (repo *Repo) createHouse(ctx context.Context, request usecase.HouseCreateRequest) (usecase.House, error) {
+	return repo.houses.Create(ctx, request)
+}
+
+func (repo *Repo) createFlat(ctx context.Context, request usecase.FlatCreateRequest) (usecase.FlatResponse, error) {
+	return repo.flats.Create(ctx, request)
+}
+
+func (repo *Repo) atomic(ctx context.Context, fn func(ctx context.Context) error) error {
+	return repo.db.WithSavepoint(ctx, func(ctx context.Context, _ pgx.Tx) error {
+		return fn(ctx)
+	})
+}
@@ -0,0 +1,111 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"testing"
+)
+
+type bulkRepoSuite struct {
+	suite.Suite
+	db   *postgres.Database
+	repo *Repo
+}
+
+func TestBulkRepoSuite(t *testing.T) {
+	suite.Run(t, new(bulkRepoSuite))
+}
+
+func (suite *bulkRepoSuite) SetupSuite() {
+	db, err := postgres.NewDB(context.Background(), suite.fromEnv())
+	suite.Require().NoError(err)
+	if err = db.GetPool().Ping(context.Background()); err != nil {
+		db.Close()
+		suite.T().Skipf("PostgreSQL is not available: %v", err)
+	}
+
+	suite.db = db
+	suite.repo = NewRepo(suite.db)
+}
+
+func (suite *bulkRepoSuite) TearDownSuite() {
+	suite.db.GetPool().Close()
+}
+
+func (suite *bulkRepoSuite) SetupTest() {
+	suite.clearTestDB(suite.db)
+}
+
+func (suite *bulkRepoSuite) TestFailedRowRollsBackItsChunk() {
+	ctx := context.Background()
+	rows := []usecase.ImportRow{
+		{Line: 1, Kind: "house", Ref: "A", Address: "123 Test St", Year: 2022},
+		{Line: 2, Kind: "flat", HouseRef: "A", Number: 1, Price: 100000, Rooms: 3},
+		{Line: 3, Kind: "flat", HouseRef: "A", Number: 2, Price: 100000, Rooms: 3},
+		// The duplicate fails in the database, the rows after it are still tried
+		{Line: 4, Kind: "flat", HouseRef: "A", Number: 2, Price: 100000, Rooms: 3},
+		{Line: 5, Kind: "flat", HouseRef: "A", Number: 3, Price: 100000, Rooms: 3},
+	}
+
+	report, err := suite.repo.Import(ctx, rows, usecase.ImportOptions{ChunkSize: 3})
+	suite.Require().NoError(err)
+	suite.Require().Equal(3, report.Imported)
+	suite.Require().ErrorIs(report.Rows[3].Err, flat.ErrDuplicateFlat)
+	suite.Require().ErrorIs(report.Rows[4].Err, ErrRolledBack)
+	suite.Require().Equal(2, suite.count(ctx, "flat"))
+}
+
+func (suite *bulkRepoSuite) TestDryRun() {
+	ctx := context.Background()
+	rows := []usecase.ImportRow{
+		{Line: 1, Kind: "house", Ref: "A", Address: "123 Test St", Year: 2022},
+		{Line: 2, Kind: "flat", HouseRef: "A", Number: 1, Price: 100000, Rooms: 3},
+	}
+
+	report, err := suite.repo.Import(ctx, rows, usecase.ImportOptions{DryRun: true, ChunkSize: 1})
+	suite.Require().NoError(err)
+	suite.Require().Equal(2, report.Imported)
+	suite.Require().Zero(suite.count(ctx, "house"))
+	suite.Require().Zero(suite.count(ctx, "flat"))
+}
+
+func (suite *bulkRepoSuite) count(ctx context.Context, table string) int {
+	var count int
+	err := suite.db.Get(ctx, &count, "SELECT count(*) FROM "+table)
+	suite.Require().NoError(err)
+	return count
+}
+
+func (suite *bulkRepoSuite) clearTestDB(db *postgres.Database) {
+	ctx := context.Background()
+	tables := []string{"house", "flat", "flat_moderation", "notification"}
+	_, err := db.Exec(ctx, "SET session_replication_role = 'replica'")
+	suite.Require().NoError(err)
+
+	for _, table := range tables {
+		_, err := db.Exec(ctx, fmt.Sprintf("TRUNCATE %s CASCADE", table))
+		suite.Require().NoError(err)
+	}
+
+	_, err = db.Exec(ctx, "SET session_replication_role = 'origin'")
+	suite.Require().NoError(err)
+}
+
+func (suite *bulkRepoSuite) fromEnv() postgres.DatabaseConfig {
+	err := godotenv.Load("../../../.env")
+	suite.Require().NoError(err)
+
+	return postgres.DatabaseConfig{
+		Host:     os.Getenv("DB_HOST"),
+		Port:     os.Getenv("DB_PORT"),
+		User:     os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASSWORD"),
+		Name:     os.Getenv("DB_NAME"),
+	}
+}
@@ -0,0 +1,62 @@
+package bulk
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+// ImportHousesAndFlats implements the import part of api.StrictServerInterface for /v2.
+func (h *Handler) ImportHousesAndFlats(ctx context.Context, request api.ImportHousesAndFlatsRequestObject) (api.ImportHousesAndFlatsResponseObject, error) {
+	var options usecase.ImportOptions
+	if request.Params.DryRun != nil {
+		options.DryRun = *request.Params.DryRun
+	}
+	if request.Params.ChunkSize != nil {
+		options.ChunkSize = *request.Params.ChunkSize
+	}
+	if err := options.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	rows, err := Parse(request.Body, string(request.Params.Format))
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := h.repo.Import(ctx, rows, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.ImportHousesAndFlats200JSONResponse(apiReport(report)), nil
+}
+
+func apiReport(report usecase.ImportReport) api.ImportReport {
+	result := api.ImportReport{
+		Total:    report.Total,
+		Imported: report.Imported,
+		Failed:   report.Failed,
+		DryRun:   report.DryRun,
+		Aborted:  report.Aborted,
+		Rows:     make([]api.ImportRowResult, 0, len(report.Rows)),
+	}
+	for _, row := range report.Rows {
+		r := api.ImportRowResult{Line: row.Line}
+		if row.Kind == "house" || row.Kind == "flat" {
+			kind := api.ImportRowResultKind(row.Kind)
+			r.Kind = &kind
+		}
+		if row.ID != 0 {
+			r.Id = &row.ID
+		}
+		if row.Err != nil {
+			apiErr := Errors.From(row.Err)
+			code := int(apiErr.Code)
+			r.Error, r.Code = &apiErr.Message, &code
+		}
+		result.Rows = append(result.Rows, r)
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/xlsx"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newStore(t *testing.T) *memory.Store {
+	ctx := context.Background()
+	store := memory.NewStore()
+	houses, flats := house.NewMemoryRepo(store), flat.NewMemoryRepo(store)
+
+	h, err := houses.Create(ctx, usecase.HouseCreateRequest{Address: "Лесная улица, 7, Москва", Year: 2000, Developer: "Мэрия города"})
+	require.NoError(t, err)
+	for number := 1; number <= 2; number++ {
+		_, err = flats.Create(ctx, usecase.FlatCreateRequest{Number: number, HouseID: h.ID, Price: 10000 * number, Rooms: number})
+		require.NoError(t, err)
+	}
+	_, err = flats.Update(ctx, usecase.FlatUpdateRequest{ID: 1, Status: "approved", ModeratorID: 7})
+	require.NoError(t, err)
+	return store
+}
+
+func visit(t *testing.T, response api.ExportFlatsResponseObject) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	require.NoError(t, response.VisitExportFlatsResponse(w))
+	return w
+}
+
+func TestExportFormats(t *testing.T) {
+	ctx := context.Background()
+	handler := NewHandler(NewMemoryRepo(newStore(t)))
+
+	houses, err := handler.ExportHouses(ctx, api.ExportHousesRequestObject{Params: api.ExportHousesParams{Format: CSV}})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	require.NoError(t, houses.VisitExportHousesResponse(w))
+	require.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	require.Equal(t, `attachment; filename="houses.csv"`, w.Header().Get("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, "id,address,year,developer,created_at,updated_at,flats,approved_flats", lines[0])
+	require.True(t, strings.HasPrefix(lines[1], `1,"Лесная улица, 7, Москва",2000,Мэрия города,`))
+	require.True(t, strings.HasSuffix(lines[1], ",2,1"))
+
+	approved := api.Status("approved")
+	flats, err := handler.ExportFlats(ctx, api.ExportFlatsRequestObject{Params: api.ExportFlatsParams{Format: NDJSON, Status: &approved}})
+	require.NoError(t, err)
+	w = visit(t, flats)
+	var exported usecase.FlatExport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &exported))
+	require.Equal(t, 1, exported.ID)
+	require.Equal(t, 1, exported.Moderations)
+	require.NotNil(t, exported.ModeratedAt)
+	require.Equal(t, 7, *exported.ModeratorID)
+
+	flats, err = handler.ExportFlats(ctx, api.ExportFlatsRequestObject{Params: api.ExportFlatsParams{Format: XLSX}})
+	require.NoError(t, err)
+	w = visit(t, flats)
+	require.Equal(t, xlsx.ContentType, w.Header().Get("Content-Type"))
+	require.Equal(t, "PK", w.Body.String()[:2])
+}
+
+// failingRepo fails after the given number of flats.
+type failingRepo struct {
+	*MemoryRepo
+	after int
+}
+
+func (repo failingRepo) Flats(ctx context.Context, request usecase.FlatExportRequest, fn func(usecase.FlatExport) error) error {
+	for i := 0; i < repo.after; i++ {
+		if err := fn(usecase.FlatExport{ID: i + 1, Status: strings.Repeat("x", 4096)}); err != nil {
+			return err
+		}
+	}
+	return errors.New("connection lost")
+}
+
+func TestExportFailure(t *testing.T) {
+	ctx := context.Background()
+	request := api.ExportFlatsRequestObject{Params: api.ExportFlatsParams{Format: CSV}}
+
+	// Nothing was sent yet, the error is reported as usual
+	response, err := NewHandler(failingRepo{after: 0}).ExportFlats(ctx, request)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	require.Error(t, response.VisitExportFlatsResponse(w))
+	require.Empty(t, w.Header().Get("Content-Disposition"))
+	require.Zero(t, w.Body.Len())
+
+	// Once rows were sent the response is aborted
+	response, err = NewHandler(failingRepo{after: 2}).ExportFlats(ctx, request)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		_ = response.VisitExportFlatsResponse(w)
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+}
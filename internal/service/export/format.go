@@ -0,0 +1,138 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/xlsx"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Formats of an export.
+const (
+	CSV    = "csv"
+	XLSX   = "xlsx"
+	NDJSON = "ndjson"
+)
+
+var contentTypes = map[string]string{
+	CSV:    "text/csv; charset=utf-8",
+	XLSX:   xlsx.ContentType,
+	NDJSON: "application/x-ndjson",
+}
+
+var (
+	houseColumns = []string{"id", "address", "year", "developer", "created_at", "updated_at", "flats", "approved_flats"}
+	flatColumns  = []string{"id", "house_id", "number", "price", "rooms", "status", "created_at", "moderations", "moderated_at", "moderator_id"}
+)
+
+// houseValues and flatValues list the cells of a row in the order of the
+// columns, nil for an empty cell.
+func houseValues(h usecase.HouseExport) []any {
+	return []any{h.ID, h.Address, h.Year, h.Developer, timestamp(&h.CreatedAt), timestamp(&h.UpdatedAt), h.Flats, h.ApprovedFlats}
+}
+
+func flatValues(f usecase.FlatExport) []any {
+	var moderatorID any
+	if f.ModeratorID != nil {
+		moderatorID = *f.ModeratorID
+	}
+	return []any{f.ID, f.HouseID, f.Number, f.Price, f.Rooms, f.Status, timestamp(&f.CreatedAt), f.Moderations, timestamp(f.ModeratedAt), moderatorID}
+}
+
+func timestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+// encoder writes the rows of an export. CSV and XLSX take the cells, NDJSON
+// the record itself so that its fields keep their JSON types.
+type encoder interface {
+	encode(record any, values []any) error
+	close() error
+}
+
+func newEncoder(w io.Writer, format, name string, columns []string) (encoder, error) {
+	header := make([]any, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+
+	var (
+		enc encoder
+		err error
+	)
+	switch format {
+	case CSV:
+		enc = csvEncoder{w: csv.NewWriter(w)}
+	case XLSX:
+		var sheet *xlsx.Writer
+		if sheet, err = xlsx.NewWriter(w, name); err != nil {
+			return nil, err
+		}
+		enc = xlsxEncoder{w: sheet}
+	case NDJSON:
+		return ndjsonEncoder{e: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	if err = enc.encode(nil, header); err != nil {
+		return nil, err
+	}
+	return enc, nil
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e csvEncoder) encode(_ any, values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case int:
+			record[i] = strconv.Itoa(v)
+		case string:
+			record[i] = v
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e csvEncoder) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type xlsxEncoder struct {
+	w *xlsx.Writer
+}
+
+func (e xlsxEncoder) encode(_ any, values []any) error {
+	return e.w.WriteRow(values)
+}
+
+func (e xlsxEncoder) close() error {
+	return e.w.Close()
+}
+
+type ndjsonEncoder struct {
+	e *json.Encoder
+}
+
+func (e ndjsonEncoder) encode(record any, _ []any) error {
+	return e.e.Encode(record)
+}
+
+func (e ndjsonEncoder) close() error {
+	return nil
+}
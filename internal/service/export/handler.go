@@ -0,0 +1,21 @@
+package export
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+// Exporter calls fn for every house or flat in the order of their ids,
+// without loading them all at once.
+type Exporter interface {
+	Houses(ctx context.Context, fn func(usecase.HouseExport) error) error
+	Flats(ctx context.Context, request usecase.FlatExportRequest, fn func(usecase.FlatExport) error) error
+}
+
+type Handler struct {
+	repo Exporter
+}
+
+func NewHandler(repo Exporter) *Handler {
+	return &Handler{repo: repo}
+}
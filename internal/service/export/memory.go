@@ -0,0 +1,79 @@
+package export
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"maps"
+	"slices"
+)
+
+var _ Exporter = (*MemoryRepo)(nil)
+
+// MemoryRepo is an Exporter backed by memory.Store, used by tests that don't need PostgreSQL.
+type MemoryRepo struct {
+	store *memory.Store
+}
+
+func NewMemoryRepo(store *memory.Store) *MemoryRepo {
+	return &MemoryRepo{
+		store: store,
+	}
+}
+
+func (repo *MemoryRepo) Houses(ctx context.Context, fn func(usecase.HouseExport) error) error {
+	repo.store.RLock()
+	houses := make([]usecase.HouseExport, 0, len(repo.store.Houses))
+	for _, id := range slices.Sorted(maps.Keys(repo.store.Houses)) {
+		h := repo.store.Houses[id]
+		house := usecase.HouseExport{ID: h.ID, Address: h.Address, Year: h.Year, Developer: h.Developer, CreatedAt: h.CreatedAt, UpdatedAt: h.UpdatedAt}
+		for _, f := range repo.store.Flats {
+			if f.HouseID == h.ID {
+				house.Flats++
+				if f.Status == "approved" {
+					house.ApprovedFlats++
+				}
+			}
+		}
+		houses = append(houses, house)
+	}
+	repo.store.RUnlock()
+
+	for _, house := range houses {
+		if err := fn(house); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *MemoryRepo) Flats(ctx context.Context, request usecase.FlatExportRequest, fn func(usecase.FlatExport) error) error {
+	repo.store.RLock()
+	flats := make([]usecase.FlatExport, 0, len(repo.store.Flats))
+	for _, id := range slices.Sorted(maps.Keys(repo.store.Flats)) {
+		f := repo.store.Flats[id]
+		if request.Status != "" && f.Status != request.Status {
+			continue
+		}
+
+		flat := usecase.FlatExport{ID: f.ID, HouseID: f.HouseID, Number: f.Number, Price: f.Price, Rooms: f.Rooms, Status: f.Status, CreatedAt: f.CreatedAt}
+		for _, m := range repo.store.Moderations {
+			if m.FlatID == f.ID {
+				flat.Moderations++
+				flat.ModeratedAt, flat.ModeratorID = &m.CreatedAt, nil
+				if m.ModeratorID != 0 {
+					flat.ModeratorID = &m.ModeratorID
+				}
+			}
+		}
+		flats = append(flats, flat)
+	}
+	repo.store.RUnlock()
+
+	for _, flat := range flats {
+		if err := fn(flat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ Exporter = (*Repo)(nil)
+
+// defaultBatchSize is how many rows are fetched from the cursor at a time.
+const defaultBatchSize = 1000
+
+type Repo struct {
+	db        *postgres.Database
+	batchSize int
+}
+
+func NewRepo(db *postgres.Database) *Repo {
+	return &Repo{
+		db:        db,
+		batchSize: defaultBatchSize,
+	}
+}
+
+func (repo *Repo) Houses(ctx context.Context, fn func(usecase.HouseExport) error) error {
+	query := `
+		SELECT h.id, h.address, h.year, coalesce(h.developer, '') AS developer, h.created_at, h.updated_at,
+			count(f.id) AS flats, count(f.id) FILTER (WHERE f.status = 'approved') AS approved_flats
+		FROM house h
+		LEFT JOIN flat f ON f.house_id = h.id
+		GROUP BY h.id
+		ORDER BY h.id
+	`
+
+	return repo.db.Stream(ctx, repo.batchSize, query, nil, scan(fn))
+}
+
+func (repo *Repo) Flats(ctx context.Context, request usecase.FlatExportRequest, fn func(usecase.FlatExport) error) error {
+	query := `
+		SELECT f.id, f.house_id, f.number, f.price, f.rooms, f.status, f.created_at,
+			coalesce(m.moderations, 0) AS moderations, m.moderated_at, m.moderator_id
+		FROM flat f
+		LEFT JOIN LATERAL (
+			SELECT count(*) OVER () AS moderations, created_at AS moderated_at, moderator_id
+			FROM flat_moderation
+			WHERE flat_id = f.id
+			ORDER BY id DESC
+			LIMIT 1
+		) m ON true
+		WHERE ($1 = '' OR f.status = $1)
+		ORDER BY f.id
+	`
+
+	return repo.db.Stream(ctx, repo.batchSize, query, []any{request.Status}, scan(fn))
+}
+
+// scan adapts fn to the rows of Stream.
+func scan[T any](fn func(T) error) func(rows pgx.Rows) error {
+	return func(rows pgx.Rows) error {
+		var record T
+		if err := pgxscan.ScanRow(&record, rows); err != nil {
+			return err
+		}
+		return fn(record)
+	}
+}
@@ -0,0 +1,119 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"testing"
+)
+
+type exportRepoSuite struct {
+	suite.Suite
+	db   *postgres.Database
+	repo *Repo
+}
+
+func TestExportRepoSuite(t *testing.T) {
+	suite.Run(t, new(exportRepoSuite))
+}
+
+func (suite *exportRepoSuite) SetupSuite() {
+	db, err := postgres.NewDB(context.Background(), suite.fromEnv())
+	suite.Require().NoError(err)
+	if err = db.GetPool().Ping(context.Background()); err != nil {
+		db.Close()
+		suite.T().Skipf("PostgreSQL is not available: %v", err)
+	}
+
+	suite.db = db
+	suite.repo = NewRepo(suite.db)
+	// Small batches make the cursor fetch several times
+	suite.repo.batchSize = 2
+}
+
+func (suite *exportRepoSuite) TearDownSuite() {
+	suite.db.GetPool().Close()
+}
+
+func (suite *exportRepoSuite) SetupTest() {
+	suite.clearTestDB(suite.db)
+}
+
+func (suite *exportRepoSuite) TestStreamsAllRows() {
+	ctx := context.Background()
+	h, err := house.NewRepo(suite.db).Create(ctx, usecase.HouseCreateRequest{Address: "123 Test St", Year: 2022})
+	suite.Require().NoError(err)
+
+	flats := flat.NewRepo(suite.db)
+	for number := 1; number <= 5; number++ {
+		_, err = flats.Create(ctx, usecase.FlatCreateRequest{Number: number, HouseID: h.ID, Price: 100000, Rooms: 3})
+		suite.Require().NoError(err)
+	}
+	approved, err := flats.FlatsByHouse(ctx, []int{h.ID}, false, 0)
+	suite.Require().NoError(err)
+	_, err = flats.Update(ctx, usecase.FlatUpdateRequest{ID: approved[0].ID, Status: "approved", ModeratorID: 7})
+	suite.Require().NoError(err)
+
+	var exported []usecase.FlatExport
+	err = suite.repo.Flats(ctx, usecase.FlatExportRequest{}, func(f usecase.FlatExport) error {
+		exported = append(exported, f)
+		return nil
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(exported, 5)
+	suite.Require().Equal(1, exported[0].Moderations)
+	suite.Require().Equal(7, *exported[0].ModeratorID)
+	suite.Require().Nil(exported[1].ModeratedAt)
+
+	exported = nil
+	err = suite.repo.Flats(ctx, usecase.FlatExportRequest{Status: "approved"}, func(f usecase.FlatExport) error {
+		exported = append(exported, f)
+		return nil
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(exported, 1)
+
+	var houses []usecase.HouseExport
+	err = suite.repo.Houses(ctx, func(h usecase.HouseExport) error {
+		houses = append(houses, h)
+		return nil
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(houses, 1)
+	suite.Require().Equal(5, houses[0].Flats)
+	suite.Require().Equal(1, houses[0].ApprovedFlats)
+}
+
+func (suite *exportRepoSuite) clearTestDB(db *postgres.Database) {
+	ctx := context.Background()
+	tables := []string{"house", "flat", "flat_moderation", "notification"}
+	_, err := db.Exec(ctx, "SET session_replication_role = 'replica'")
+	suite.Require().NoError(err)
+
+	for _, table := range tables {
+		_, err := db.Exec(ctx, fmt.Sprintf("TRUNCATE %s CASCADE", table))
+		suite.Require().NoError(err)
+	}
+
+	_, err = db.Exec(ctx, "SET session_replication_role = 'origin'")
+	suite.Require().NoError(err)
+}
+
+func (suite *exportRepoSuite) fromEnv() postgres.DatabaseConfig {
+	err := godotenv.Load("../../../.env")
+	suite.Require().NoError(err)
+
+	return postgres.DatabaseConfig{
+		Host:     os.Getenv("DB_HOST"),
+		Port:     os.Getenv("DB_PORT"),
+		User:     os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASSWORD"),
+		Name:     os.Getenv("DB_NAME"),
+	}
+}
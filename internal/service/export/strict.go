@@ -0,0 +1,107 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"net/http"
+)
+
+// The methods below implement the export part of api.StrictServerInterface for /v2.
+
+func (h *Handler) ExportHouses(ctx context.Context, request api.ExportHousesRequestObject) (api.ExportHousesResponseObject, error) {
+	format := string(request.Params.Format)
+	if _, ok := contentTypes[format]; !ok {
+		return nil, apierror.InvalidParameter("format must be csv, xlsx or ndjson")
+	}
+
+	return exportResponse{ctx: ctx, name: "houses", format: format, columns: houseColumns, export: func(enc encoder) error {
+		return h.repo.Houses(ctx, func(house usecase.HouseExport) error {
+			return enc.encode(house, houseValues(house))
+		})
+	}}, nil
+}
+
+func (h *Handler) ExportFlats(ctx context.Context, request api.ExportFlatsRequestObject) (api.ExportFlatsResponseObject, error) {
+	format := string(request.Params.Format)
+	if _, ok := contentTypes[format]; !ok {
+		return nil, apierror.InvalidParameter("format must be csv, xlsx or ndjson")
+	}
+
+	var req usecase.FlatExportRequest
+	if request.Params.Status != nil {
+		req.Status = string(*request.Params.Status)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	return exportResponse{ctx: ctx, name: "flats", format: format, columns: flatColumns, export: func(enc encoder) error {
+		return h.repo.Flats(ctx, req, func(flat usecase.FlatExport) error {
+			return enc.encode(flat, flatValues(flat))
+		})
+	}}, nil
+}
+
+// exportResponse streams the export while the repository reads it. The status
+// is sent with the first bytes of the body: an error before that is reported
+// as usual, an error after it aborts the response, so that the client sees
+// a truncated transfer rather than a complete file.
+type exportResponse struct {
+	ctx     context.Context
+	name    string
+	format  string
+	columns []string
+	export  func(enc encoder) error
+}
+
+func (response exportResponse) VisitExportHousesResponse(w http.ResponseWriter) error {
+	return response.visit(w)
+}
+
+func (response exportResponse) VisitExportFlatsResponse(w http.ResponseWriter) error {
+	return response.visit(w)
+}
+
+func (response exportResponse) visit(w http.ResponseWriter) error {
+	body := &lazyWriter{ResponseWriter: w, response: response}
+
+	err := response.write(body)
+	if err == nil || !body.started {
+		return err
+	}
+
+	logger.FromContext(response.ctx).Error("Export failed after the response started", "export", response.name, "error", err)
+	panic(http.ErrAbortHandler)
+}
+
+func (response exportResponse) write(w *lazyWriter) error {
+	enc, err := newEncoder(w, response.format, response.name, response.columns)
+	if err != nil {
+		return err
+	}
+	if err = response.export(enc); err != nil {
+		return err
+	}
+	return enc.close()
+}
+
+// lazyWriter sends the headers of the export on the first write.
+type lazyWriter struct {
+	http.ResponseWriter
+	response exportResponse
+	started  bool
+}
+
+func (w *lazyWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", contentTypes[w.response.format])
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, w.response.name, w.response.format))
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
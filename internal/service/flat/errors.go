@@ -1,9 +1,20 @@
 package flat
 
-import "errors"
+import (
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"net/http"
+)
 
 var (
 	ErrHouseNotFound = errors.New("house with the given ID does not exist")
 	ErrFlatNotFound  = errors.New("flat with the given ID does not exist")
 	ErrDuplicateFlat = errors.New("flat with this number already exists in the specified house")
 )
+
+// Errors maps the errors of the package to their API representation.
+var Errors = apierror.Mapper{
+	apierror.Map(ErrHouseNotFound, http.StatusNotFound, apierror.CodeHouseNotFound, "House with this ID does not exist"),
+	apierror.Map(ErrFlatNotFound, http.StatusNotFound, apierror.CodeFlatNotFound, "Flat does not exist"),
+	apierror.Map(ErrDuplicateFlat, http.StatusConflict, apierror.CodeDuplicateFlat, "Flat with this number already exists in the house"),
+}
@@ -3,84 +3,131 @@ package flat
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
-	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
 	"net/http"
+	"strconv"
 )
 
 type Flat interface {
 	Create(ctx context.Context, request usecase.FlatCreateRequest) (usecase.FlatResponse, error)
 	Update(ctx context.Context, request usecase.FlatUpdateRequest) (usecase.FlatResponse, error)
+	Search(ctx context.Context, request usecase.FlatSearchRequest) (usecase.FlatSearchResponse, error)
 }
 
 type Handler struct {
 	repo Flat
 }
 
-func NewHandler(db *postgres.Database) *Handler {
-	return &Handler{repo: NewRepo(db)}
+func NewHandler(repo Flat) *Handler {
+	return &Handler{repo: repo}
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var req usecase.FlatCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		Errors.Write(w, r, apierror.ErrInvalidPayload)
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		Errors.Write(w, r, apierror.Validation(err))
 		return
 	}
 
 	response, err := h.repo.Create(r.Context(), req)
 	if err != nil {
-		if errors.Is(err, ErrHouseNotFound) {
-			http.Error(w, "House with this ID does not exist", http.StatusNotFound)
-			return
-		}
-
-		if errors.Is(err, ErrDuplicateFlat) {
-			http.Error(w, "House with this ID already exists", http.StatusConflict)
-			return
-		}
-
-		http.Error(w, "Failed to create flat", http.StatusInternalServerError)
+		Errors.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err = json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to create flat", http.StatusInternalServerError)
+		Errors.Write(w, r, apierror.Internal("Failed to create flat", err))
 	}
 }
 
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	var req usecase.FlatUpdateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		Errors.Write(w, r, apierror.ErrInvalidPayload)
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		Errors.Write(w, r, apierror.Validation(err))
 		return
 	}
+	if claims, ok := r.Context().Value("claims").(*auth.Claims); ok {
+		req.ModeratorID = claims.UserID
+	}
 
 	response, err := h.repo.Update(r.Context(), req)
 	if err != nil {
-		if errors.Is(err, ErrFlatNotFound) {
-			http.Error(w, "Flat does not exist", http.StatusNotFound)
+		Errors.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		Errors.Write(w, r, apierror.Internal("Failed to update flat", err))
+	}
+}
+
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.Claims)
+	if !ok {
+		Errors.Write(w, r, apierror.ErrMissingClaims)
+		return
+	}
+
+	query := r.URL.Query()
+	req := usecase.FlatSearchRequest{
+		Developer:    query.Get("developer"),
+		Sort:         query.Get("sort"),
+		ApprovedOnly: !claims.Can(usecase.PermFlatModerate),
+	}
+
+	params := []struct {
+		name  string
+		value *int
+	}{
+		{"price_from", &req.PriceFrom},
+		{"price_to", &req.PriceTo},
+		{"rooms", &req.Rooms},
+		{"year_from", &req.YearFrom},
+		{"year_to", &req.YearTo},
+		{"limit", &req.Limit},
+		{"offset", &req.Offset},
+	}
+	for _, param := range params {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			Errors.Write(w, r, apierror.InvalidParameter(param.name+" must be integer"))
 			return
 		}
+		*param.value = value
+	}
 
-		http.Error(w, "Failed to update flat", http.StatusInternalServerError)
+	if err := req.Validate(); err != nil {
+		Errors.Write(w, r, apierror.Validation(err))
+		return
+	}
+
+	response, err := h.repo.Search(r.Context(), req)
+	if err != nil {
+		Errors.Write(w, r, apierror.Internal("Failed to search flats", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err = json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to update flat", http.StatusInternalServerError)
+		Errors.Write(w, r, apierror.Internal("Failed to search flats", err))
 	}
 }
@@ -5,21 +5,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/house"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
-	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
-	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/suite"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
+	"time"
 )
 
 type flatHandlerSuite struct {
 	suite.Suite
-	db      *postgres.Database
 	auth    *auth.Handler
 	house   *house.Handler
 	handler *Handler
@@ -29,18 +28,13 @@ func TestFlatHandlerSuite(t *testing.T) {
 	suite.Run(t, new(flatHandlerSuite))
 }
 
-func (suite *flatHandlerSuite) SetupSuite() {
-	db, err := postgres.NewDB(context.Background(), suite.fromEnv())
-	suite.Require().NoError(err)
-
-	suite.db = db
-	suite.auth = auth.NewHandler(db)
-	suite.house = house.NewHandler(db)
-	suite.handler = NewHandler(db)
-}
-
 func (suite *flatHandlerSuite) SetupTest() {
-	suite.clearTestDB(suite.db)
+	store := memory.NewStore()
+	tokens := auth.NewTokenManager(auth.Config{Secret: "test-secret", TokenTTL: time.Hour, Issuer: "test"})
+
+	suite.auth = auth.NewHandler(auth.NewMemoryRepo(store, tokens), tokens)
+	suite.house = house.NewHandler(house.NewMemoryRepo(store))
+	suite.handler = NewHandler(NewMemoryRepo(store))
 }
 
 func (suite *flatHandlerSuite) TestCreateFailDecoding() {
@@ -124,8 +118,11 @@ func (suite *flatHandlerSuite) TestCreateFailDuplicateFlat() {
 
 	suite.Require().EqualValues(http.StatusConflict, duplicateRecorder.Code)
 
-	expectedErrorMessage := "House with this ID already exists"
-	suite.Require().Contains(duplicateRecorder.Body.String(), expectedErrorMessage)
+	var response apierror.Error
+	err = json.NewDecoder(duplicateRecorder.Body).Decode(&response)
+	suite.Require().NoError(err)
+	suite.Require().EqualValues("Flat with this number already exists in the house", response.Message)
+	suite.Require().EqualValues(apierror.CodeDuplicateFlat, response.Code)
 }
 
 func (suite *flatHandlerSuite) TestCreateSuccess() {
@@ -207,8 +204,11 @@ func (suite *flatHandlerSuite) TestUpdateFlatNotFound() {
 
 	suite.Require().EqualValues(http.StatusNotFound, recorder.Code)
 
-	expectedErrorMessage := "Flat does not exist"
-	suite.Require().Contains(recorder.Body.String(), expectedErrorMessage)
+	var response apierror.Error
+	err := json.NewDecoder(recorder.Body).Decode(&response)
+	suite.Require().NoError(err)
+	suite.Require().EqualValues("Flat does not exist", response.Message)
+	suite.Require().EqualValues(apierror.CodeFlatNotFound, response.Code)
 }
 
 func (suite *flatHandlerSuite) TestUpdateSuccess() {
@@ -255,6 +255,54 @@ func (suite *flatHandlerSuite) TestUpdateSuccess() {
 	suite.Require().EqualValues("approved", updatedFlat.Status)
 }
 
+func (suite *flatHandlerSuite) TestSearchFailValidation() {
+	req := httptest.NewRequest(http.MethodGet, "/flats?price_from=500&price_to=100", nil)
+	req = req.WithContext(context.WithValue(req.Context(), "claims", &auth.Claims{UserID: 1, Role: "client", Permissions: usecase.DefaultRoles["client"]}))
+
+	w := httptest.NewRecorder()
+	suite.handler.Search(w, req)
+
+	suite.Require().EqualValues(http.StatusBadRequest, w.Code)
+	suite.Require().Contains(w.Body.String(), "Field validation for 'PriceTo' failed on the 'gtefield' tag")
+}
+
+func (suite *flatHandlerSuite) TestSearchClientSeesApprovedOnly() {
+	houseID := suite.createHouse()
+
+	var created []usecase.FlatResponse
+	for _, number := range []int{101, 102} {
+		body := fmt.Sprintf(`{"number": %d, "house_id": %d, "price": 1000, "rooms": 3}`, number, houseID)
+		req := httptest.NewRequest(http.MethodPost, "/flat/create", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+		suite.handler.Create(w, req)
+		suite.Require().EqualValues(http.StatusOK, w.Code)
+
+		var flat usecase.FlatResponse
+		suite.Require().NoError(json.NewDecoder(w.Body).Decode(&flat))
+		created = append(created, flat)
+	}
+
+	updateBody := fmt.Sprintf(`{"id": %d, "status": "approved"}`, created[0].ID)
+	updateReq := httptest.NewRequest(http.MethodPost, "/flat/update", bytes.NewReader([]byte(updateBody)))
+	updateRecorder := httptest.NewRecorder()
+	suite.handler.Update(updateRecorder, updateReq)
+	suite.Require().EqualValues(http.StatusOK, updateRecorder.Code)
+
+	for role, expected := range map[string]int{"client": 1, "moderator": 2} {
+		req := httptest.NewRequest(http.MethodGet, "/flats", nil)
+		req = req.WithContext(context.WithValue(req.Context(), "claims", &auth.Claims{UserID: 1, Role: role, Permissions: usecase.DefaultRoles[role]}))
+
+		w := httptest.NewRecorder()
+		suite.handler.Search(w, req)
+		suite.Require().EqualValues(http.StatusOK, w.Code)
+
+		var response usecase.FlatSearchResponse
+		suite.Require().NoError(json.NewDecoder(w.Body).Decode(&response))
+		suite.Require().EqualValues(expected, response.Total, role)
+		suite.Require().Len(response.Flats, expected, role)
+	}
+}
+
 func (suite *flatHandlerSuite) createHouse() int {
 	validRequest := `{
 		"address": "Садовая улица, 15, Москва, 123456",
@@ -291,33 +339,3 @@ func (suite *flatHandlerSuite) moderatorToken() string {
 	suite.Require().NoError(err)
 	return loginResponse.Token
 }
-
-func (suite *flatHandlerSuite) clearTestDB(db *postgres.Database) {
-	ctx := context.Background()
-	tables := []string{`"user"`, "flat", "house"}
-	_, err := db.Exec(ctx, "SET session_replication_role = 'replica'")
-	suite.Require().NoError(err)
-
-	for _, table := range tables {
-		_, err := db.Exec(ctx, fmt.Sprintf("TRUNCATE %s CASCADE", table))
-		suite.Require().NoError(err)
-	}
-
-	_, err = db.Exec(ctx, "SET session_replication_role = 'origin'")
-	suite.Require().NoError(err)
-
-	fmt.Println("Test database cleared successfully")
-}
-
-func (suite *flatHandlerSuite) fromEnv() postgres.DatabaseConfig {
-	err := godotenv.Load("../../../.env")
-	suite.Require().NoError(err)
-
-	return postgres.DatabaseConfig{
-		Host:     os.Getenv("DB_HOST"),
-		Port:     os.Getenv("DB_PORT"),
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		Name:     os.Getenv("DB_NAME"),
-	}
-}
@@ -0,0 +1,227 @@
+package flat
+
+import (
+	"context"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"slices"
+	"strings"
+	"time"
+)
+
+const created = "created"
+
+var (
+	_ Flat        = (*MemoryRepo)(nil)
+	_ BatchReader = (*MemoryRepo)(nil)
+)
+
+// MemoryRepo is a Flat backed by memory.Store, used by tests that don't need PostgreSQL.
+type MemoryRepo struct {
+	store *memory.Store
+}
+
+func NewMemoryRepo(store *memory.Store) *MemoryRepo {
+	return &MemoryRepo{
+		store: store,
+	}
+}
+
+func (repo *MemoryRepo) Create(ctx context.Context, request usecase.FlatCreateRequest) (usecase.FlatResponse, error) {
+	repo.store.Lock()
+	defer repo.store.Unlock()
+
+	house, ok := repo.store.Houses[request.HouseID]
+	if !ok {
+		return usecase.FlatResponse{}, ErrHouseNotFound
+	}
+
+	for _, flat := range repo.store.Flats {
+		if flat.HouseID == request.HouseID && flat.Number == request.Number {
+			return usecase.FlatResponse{}, ErrDuplicateFlat
+		}
+	}
+
+	now := time.Now()
+	flat := memory.Flat{
+		FlatResponse: usecase.FlatResponse{
+			ID:      repo.store.NextID("flat"),
+			Number:  request.Number,
+			HouseID: request.HouseID,
+			Price:   request.Price,
+			Rooms:   request.Rooms,
+			Status:  created,
+		},
+		CreatedAt: now,
+	}
+	repo.store.Flats[flat.ID] = flat
+
+	house.UpdatedAt = now
+	repo.store.Houses[house.ID] = house
+
+	return flat.FlatResponse, nil
+}
+
+func (repo *MemoryRepo) Update(ctx context.Context, request usecase.FlatUpdateRequest) (usecase.FlatResponse, error) {
+	repo.store.Lock()
+	defer repo.store.Unlock()
+
+	flat, ok := repo.store.Flats[request.ID]
+	if !ok {
+		return usecase.FlatResponse{}, ErrFlatNotFound
+	}
+
+	previousStatus := flat.Status
+	flat.Status = request.Status
+	repo.store.Flats[flat.ID] = flat
+
+	now := time.Now()
+	if house, ok := repo.store.Houses[flat.HouseID]; ok {
+		house.UpdatedAt = now
+		repo.store.Houses[house.ID] = house
+	}
+
+	repo.store.Moderations = append(repo.store.Moderations, usecase.Moderation{
+		ID:          repo.store.NextID("flat_moderation"),
+		FlatID:      flat.ID,
+		ModeratorID: request.ModeratorID,
+		FromStatus:  previousStatus,
+		ToStatus:    flat.Status,
+		CreatedAt:   now,
+	})
+
+	if flat.Status == approved && previousStatus != approved {
+		if email, ok := repo.store.Subscribers[flat.HouseID]; ok {
+			repo.store.Notifications = append(repo.store.Notifications, usecase.Notification{
+				ID:      repo.store.NextID("notification"),
+				Email:   email,
+				Message: fmt.Sprintf("В доме %d появилась новая квартира №%d", flat.HouseID, flat.Number),
+			})
+		}
+	}
+
+	return flat.FlatResponse, nil
+}
+
+func (repo *MemoryRepo) Search(ctx context.Context, request usecase.FlatSearchRequest) (usecase.FlatSearchResponse, error) {
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	response := usecase.FlatSearchResponse{
+		Flats:  make([]usecase.FlatResponse, 0),
+		Limit:  limit,
+		Offset: request.Offset,
+	}
+
+	matches := make([]memory.Flat, 0)
+
+	repo.store.RLock()
+	for _, flat := range repo.store.Flats {
+		house := repo.store.Houses[flat.HouseID]
+		switch {
+		case request.ApprovedOnly && flat.Status != approved,
+			request.PriceFrom != 0 && flat.Price < request.PriceFrom,
+			request.PriceTo != 0 && flat.Price > request.PriceTo,
+			request.Rooms != 0 && flat.Rooms != request.Rooms,
+			request.YearFrom != 0 && house.Year < request.YearFrom,
+			request.YearTo != 0 && house.Year > request.YearTo,
+			request.Developer != "" && !strings.EqualFold(house.Developer, request.Developer):
+			continue
+		}
+		matches = append(matches, flat)
+	}
+	repo.store.RUnlock()
+
+	slices.SortFunc(matches, func(a, b memory.Flat) int {
+		switch request.Sort {
+		case "price_asc":
+			if a.Price != b.Price {
+				return a.Price - b.Price
+			}
+			return a.ID - b.ID
+		case "price_desc":
+			if a.Price != b.Price {
+				return b.Price - a.Price
+			}
+			return b.ID - a.ID
+		default:
+			if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+				return c
+			}
+			return b.ID - a.ID
+		}
+	})
+
+	response.Total = len(matches)
+	if request.Offset >= len(matches) {
+		return response, nil
+	}
+
+	for _, flat := range matches[request.Offset:min(request.Offset+limit, len(matches))] {
+		response.Flats = append(response.Flats, flat.FlatResponse)
+	}
+
+	return response, nil
+}
+
+func (repo *MemoryRepo) FlatsByID(ctx context.Context, ids []int) ([]usecase.FlatResponse, error) {
+	repo.store.RLock()
+	defer repo.store.RUnlock()
+
+	flats := make([]usecase.FlatResponse, 0, len(ids))
+	for _, id := range ids {
+		if flat, ok := repo.store.Flats[id]; ok {
+			flats = append(flats, flat.FlatResponse)
+		}
+	}
+
+	return flats, nil
+}
+
+func (repo *MemoryRepo) FlatsByHouse(ctx context.Context, houseIDs []int, approvedOnly bool, limit int) ([]usecase.FlatResponse, error) {
+	repo.store.RLock()
+	defer repo.store.RUnlock()
+
+	flats := make([]usecase.FlatResponse, 0)
+	for _, flat := range repo.store.Flats {
+		if !slices.Contains(houseIDs, flat.HouseID) || approvedOnly && flat.Status != approved {
+			continue
+		}
+		flats = append(flats, flat.FlatResponse)
+	}
+
+	slices.SortFunc(flats, func(a, b usecase.FlatResponse) int {
+		if a.HouseID != b.HouseID {
+			return a.HouseID - b.HouseID
+		}
+		return a.Number - b.Number
+	})
+
+	if limit > 0 {
+		count := make(map[int]int)
+		flats = slices.DeleteFunc(flats, func(flat usecase.FlatResponse) bool {
+			count[flat.HouseID]++
+			return count[flat.HouseID] > limit
+		})
+	}
+
+	return flats, nil
+}
+
+func (repo *MemoryRepo) ModerationsByFlat(ctx context.Context, flatIDs []int) ([]usecase.Moderation, error) {
+	repo.store.RLock()
+	defer repo.store.RUnlock()
+
+	// Moderations are appended in id order, so each flat's history stays oldest first
+	moderations := make([]usecase.Moderation, 0)
+	for _, moderation := range repo.store.Moderations {
+		if slices.Contains(flatIDs, moderation.FlatID) {
+			moderations = append(moderations, moderation)
+		}
+	}
+
+	return moderations, nil
+}
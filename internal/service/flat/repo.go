@@ -3,13 +3,51 @@ package flat
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/metrics"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/outbox"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-var _ Flat = (*Repo)(nil)
+var (
+	_ Flat        = (*Repo)(nil)
+	_ BatchReader = (*Repo)(nil)
+)
+
+// BatchReader loads flats and their moderation history for many keys at once,
+// so that GraphQL resolvers can batch their lookups.
+type BatchReader interface {
+	FlatsByID(ctx context.Context, ids []int) ([]usecase.FlatResponse, error)
+	FlatsByHouse(ctx context.Context, houseIDs []int, approvedOnly bool, limit int) ([]usecase.FlatResponse, error)
+	ModerationsByFlat(ctx context.Context, flatIDs []int) ([]usecase.Moderation, error)
+}
+
+const (
+	approved           = "approved"
+	defaultSearchLimit = 20
+
+	searchFilter = `
+		FROM flat f
+		JOIN house h ON h.id = f.house_id
+		WHERE ($1 = false OR f.status = 'approved')
+			AND ($2 = 0 OR f.price >= $2)
+			AND ($3 = 0 OR f.price <= $3)
+			AND ($4 = 0 OR f.rooms = $4)
+			AND ($5 = 0 OR h.year >= $5)
+			AND ($6 = 0 OR h.year <= $6)
+			AND ($7 = '' OR lower(h.developer) = lower($7))
+	`
+)
+
+var searchOrders = map[string]string{
+	"price_asc":  "f.price ASC, f.id ASC",
+	"price_desc": "f.price DESC, f.id DESC",
+	"newest":     "f.created_at DESC, f.id DESC",
+}
 
 type Repo struct {
 	db *postgres.Database
@@ -22,95 +60,210 @@ func NewRepo(db *postgres.Database) *Repo {
 }
 
 func (repo *Repo) Create(ctx context.Context, request usecase.FlatCreateRequest) (usecase.FlatResponse, error) {
-	tx, err := repo.db.BeginTx(ctx)
+	var response usecase.FlatResponse
+	err := repo.db.WithTx(ctx, postgres.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+		var houseExists bool
+		row := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM house WHERE id=$1)", request.HouseID)
+		err := row.Scan(&houseExists)
+		if err != nil {
+			return err
+		}
+
+		if !houseExists {
+			return ErrHouseNotFound
+		}
+
+		query := `
+			INSERT INTO flat (number, house_id, price, rooms)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, number, house_id, price, rooms, status
+		`
+		row = tx.QueryRow(ctx, query, request.Number, request.HouseID, request.Price, request.Rooms)
+		err = row.Scan(&response.ID, &response.Number, &response.HouseID, &response.Price, &response.Rooms, &response.Status)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return ErrDuplicateFlat
+			}
+			return err
+		}
+
+		updateHouseQuery := `
+			UPDATE house
+			SET updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+		`
+		_, err = tx.Exec(ctx, updateHouseQuery, request.HouseID)
+		return err
+	})
 	if err != nil {
 		return usecase.FlatResponse{}, err
 	}
-	defer repo.db.RollbackTx(ctx, tx)
 
-	var houseExists bool
-	row := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM house WHERE id=$1)", request.HouseID)
-	err = row.Scan(&houseExists)
+	return response, nil
+}
+
+func (repo *Repo) Update(ctx context.Context, request usecase.FlatUpdateRequest) (usecase.FlatResponse, error) {
+	var (
+		response       usecase.FlatResponse
+		previousStatus string
+	)
+	err := repo.db.WithTx(ctx, postgres.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+		query := `
+			UPDATE flat f
+			SET status = $1
+			FROM (SELECT id, status FROM flat WHERE id = $2 FOR UPDATE) AS previous
+			WHERE f.id = previous.id
+			RETURNING f.id, f.number, f.house_id, f.price, f.rooms, f.status, coalesce(previous.status, '')
+		`
+		row := tx.QueryRow(ctx, query, request.Status, request.ID)
+		err := row.Scan(&response.ID, &response.Number, &response.HouseID, &response.Price, &response.Rooms, &response.Status, &previousStatus)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrFlatNotFound
+			}
+			return err
+		}
+
+		updateHouseQuery := `
+			UPDATE house
+			SET updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+		`
+		_, err = tx.Exec(ctx, updateHouseQuery, response.HouseID)
+		if err != nil {
+			return err
+		}
+
+		historyQuery := `
+			INSERT INTO flat_moderation (flat_id, moderator_id, from_status, to_status)
+			VALUES ($1, NULLIF($2, 0), $3, $4)
+		`
+		_, err = tx.Exec(ctx, historyQuery, response.ID, request.ModeratorID, previousStatus, response.Status)
+		if err != nil {
+			return err
+		}
+
+		if response.Status == approved && previousStatus != approved {
+			// Уведомления пишутся в той же транзакции и отправляются воркером sender
+			message := fmt.Sprintf("В доме %d появилась новая квартира №%d", response.HouseID, response.Number)
+			err = outbox.Enqueue(ctx, tx, response.HouseID, message)
+		}
+		return err
+	})
 	if err != nil {
 		return usecase.FlatResponse{}, err
 	}
 
-	if !houseExists {
-		return usecase.FlatResponse{}, ErrHouseNotFound
+	metrics.FlatModerations.WithLabelValues(previousStatus, response.Status).Inc()
+	logger.FromContext(ctx).Info("Flat status updated",
+		"flat_id", response.ID, "house_id", response.HouseID, "from", previousStatus, "to", response.Status)
+
+	return response, nil
+}
+
+func (repo *Repo) Search(ctx context.Context, request usecase.FlatSearchRequest) (usecase.FlatSearchResponse, error) {
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
 	}
 
-	query := `
-		INSERT INTO flat (number, house_id, price, rooms)
-		VALUES ($1, $2, $3, $4)
-		RETURNING *
-	`
-	var response usecase.FlatResponse
-	row = tx.QueryRow(ctx, query, request.Number, request.HouseID, request.Price, request.Rooms)
-	err = row.Scan(&response.ID, &response.Number, &response.HouseID, &response.Price, &response.Rooms, &response.Status)
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return usecase.FlatResponse{}, ErrDuplicateFlat
-		}
-		return usecase.FlatResponse{}, err
+	order, ok := searchOrders[request.Sort]
+	if !ok {
+		order = searchOrders["newest"]
 	}
 
-	updateHouseQuery := `
-		UPDATE house
-		SET updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1
-	`
-	_, err = tx.Exec(ctx, updateHouseQuery, request.HouseID)
+	response := usecase.FlatSearchResponse{
+		Flats:  make([]usecase.FlatResponse, 0),
+		Limit:  limit,
+		Offset: request.Offset,
+	}
+	args := []interface{}{
+		request.ApprovedOnly,
+		request.PriceFrom,
+		request.PriceTo,
+		request.Rooms,
+		request.YearFrom,
+		request.YearTo,
+		request.Developer,
+	}
+
+	err := repo.db.Get(ctx, &response.Total, "SELECT count(*)"+searchFilter, args...)
 	if err != nil {
-		return usecase.FlatResponse{}, err
+		return response, err
+	}
+
+	if response.Total <= request.Offset {
+		return response, nil
 	}
 
-	err = repo.db.CommitTx(ctx, tx)
+	query := `SELECT f.id, f.number, f.house_id, f.price, f.rooms, f.status` + searchFilter +
+		`ORDER BY ` + order + ` LIMIT $8 OFFSET $9`
+	err = repo.db.Select(ctx, &response.Flats, query, append(args, limit, request.Offset)...)
 	if err != nil {
-		return usecase.FlatResponse{}, err
+		return response, err
 	}
 
 	return response, nil
 }
 
-func (repo *Repo) Update(ctx context.Context, request usecase.FlatUpdateRequest) (usecase.FlatResponse, error) {
-	tx, err := repo.db.BeginTx(ctx)
-	if err != nil {
-		return usecase.FlatResponse{}, err
-	}
-	defer repo.db.RollbackTx(ctx, tx)
+// FlatsByID returns the flats with the given ids, missing ids are skipped.
+func (repo *Repo) FlatsByID(ctx context.Context, ids []int) ([]usecase.FlatResponse, error) {
+	query := `
+		SELECT id, number, house_id, price, rooms, status
+		FROM flat
+		WHERE id = ANY($1)
+	`
+
+	flats := make([]usecase.FlatResponse, 0, len(ids))
+	err := repo.db.Select(ctx, &flats, query, ids)
+	return flats, err
+}
 
+// FlatsByStatus returns the flats with the given status ordered by id.
+func (repo *Repo) FlatsByStatus(ctx context.Context, status string) ([]usecase.FlatResponse, error) {
 	query := `
-		UPDATE flat
-		SET status = $1
-		WHERE id = $2
-		RETURNING *
+		SELECT id, number, house_id, price, rooms, status
+		FROM flat
+		WHERE status = $1
+		ORDER BY id
 	`
 
-	var response usecase.FlatResponse
-	row := tx.QueryRow(ctx, query, request.Status, request.ID)
-	err = row.Scan(&response.ID, &response.Number, &response.HouseID, &response.Price, &response.Rooms, &response.Status)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return usecase.FlatResponse{}, ErrFlatNotFound
-		}
-		return usecase.FlatResponse{}, err
-	}
+	flats := make([]usecase.FlatResponse, 0)
+	err := repo.db.Select(ctx, &flats, query, status)
+	return flats, err
+}
 
-	updateHouseQuery := `
-		UPDATE house
-		SET updated_at = CURRENT_TIMESTAMP
-		WHERE id = (SELECT house_id FROM flat WHERE id = $1)
+// FlatsByHouse returns the flats of the given houses ordered by house and
+// number, at most limit of each house. Limit 0 returns all of them.
+func (repo *Repo) FlatsByHouse(ctx context.Context, houseIDs []int, approvedOnly bool, limit int) ([]usecase.FlatResponse, error) {
+	query := `
+		SELECT id, number, house_id, price, rooms, status
+		FROM (
+			SELECT id, number, house_id, price, rooms, status,
+				row_number() OVER (PARTITION BY house_id ORDER BY number) AS position
+			FROM flat
+			WHERE house_id = ANY($1) AND ($2 = false OR status = 'approved')
+		) AS f
+		WHERE $3 = 0 OR position <= $3
+		ORDER BY house_id, number
 	`
-	_, err = tx.Exec(ctx, updateHouseQuery, request.ID)
-	if err != nil {
-		return usecase.FlatResponse{}, err
-	}
 
-	err = repo.db.CommitTx(ctx, tx)
-	if err != nil {
-		return usecase.FlatResponse{}, err
-	}
+	flats := make([]usecase.FlatResponse, 0)
+	err := repo.db.Select(ctx, &flats, query, houseIDs, approvedOnly, limit)
+	return flats, err
+}
 
-	return response, nil
+// ModerationsByFlat returns the status changes of the given flats, oldest first.
+func (repo *Repo) ModerationsByFlat(ctx context.Context, flatIDs []int) ([]usecase.Moderation, error) {
+	query := `
+		SELECT id, flat_id, coalesce(moderator_id, 0) AS moderator_id, from_status, to_status, created_at
+		FROM flat_moderation
+		WHERE flat_id = ANY($1)
+		ORDER BY flat_id, id
+	`
+
+	moderations := make([]usecase.Moderation, 0)
+	err := repo.db.Select(ctx, &moderations, query, flatIDs)
+	return moderations, err
 }
@@ -2,9 +2,11 @@ package flat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"github.com/jackc/pgx/v5"
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/suite"
 	"os"
@@ -24,6 +26,10 @@ func TestFlatRepoSuite(t *testing.T) {
 func (suite *flatRepoSuite) SetupSuite() {
 	db, err := postgres.NewDB(context.Background(), suite.fromEnv())
 	suite.Require().NoError(err)
+	if err = db.GetPool().Ping(context.Background()); err != nil {
+		db.Close()
+		suite.T().Skipf("PostgreSQL is not available: %v", err)
+	}
 
 	suite.db = db
 	suite.repo = NewRepo(suite.db)
@@ -123,6 +129,130 @@ func (suite *flatRepoSuite) TestUpdateFlatNotFound() {
 	suite.Require().ErrorIs(err, ErrFlatNotFound)
 }
 
+func (suite *flatRepoSuite) TestUpdateApprovedEnqueuesNotifications() {
+	ctx := context.Background()
+	houseID := suite.insertTestHouse(ctx, "123 Test Street", 2022)
+	_, err := suite.db.Exec(ctx, `INSERT INTO subscriber (email, house_id) VALUES ($1, $2)`, "test@example.com", houseID)
+	suite.Require().NoError(err)
+
+	flat, err := suite.repo.Create(ctx, usecase.FlatCreateRequest{Number: 1, HouseID: houseID, Price: 100000, Rooms: 3})
+	suite.Require().NoError(err)
+
+	for _, status := range []string{"on_moderate", "approved", "approved"} {
+		_, err = suite.repo.Update(ctx, usecase.FlatUpdateRequest{ID: flat.ID, Status: status})
+		suite.Require().NoError(err)
+	}
+
+	var emails []string
+	err = suite.db.Select(ctx, &emails, `SELECT email FROM notification WHERE status = 'pending'`)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"test@example.com"}, emails)
+}
+
+func (suite *flatRepoSuite) TestCreateJoinsOuterTransaction() {
+	ctx := context.Background()
+	houseID := suite.insertTestHouse(ctx, "123 Test Street", 2022)
+	errAbort := errors.New("abort")
+
+	err := suite.db.WithTx(ctx, postgres.TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+		_, err := suite.repo.Create(ctx, usecase.FlatCreateRequest{Number: 1, HouseID: houseID, Price: 100000, Rooms: 3})
+		suite.Require().NoError(err)
+		return errAbort
+	})
+	suite.Require().ErrorIs(err, errAbort)
+
+	response, err := suite.repo.Search(ctx, usecase.FlatSearchRequest{})
+	suite.Require().NoError(err)
+	suite.Require().Zero(response.Total)
+}
+
+func (suite *flatRepoSuite) TestSearchVisibilityAndFilters() {
+	ctx := context.Background()
+	oldHouseID := suite.insertTestHouse(ctx, "123 Test Street", 1990)
+	newHouseID := suite.insertTestHouse(ctx, "456 Test Street", 2022)
+
+	flats := []usecase.FlatCreateRequest{
+		{Number: 1, HouseID: oldHouseID, Price: 100000, Rooms: 1},
+		{Number: 2, HouseID: oldHouseID, Price: 200000, Rooms: 2},
+		{Number: 1, HouseID: newHouseID, Price: 300000, Rooms: 2},
+		{Number: 2, HouseID: newHouseID, Price: 400000, Rooms: 3},
+	}
+	for i, request := range flats {
+		flat, err := suite.repo.Create(ctx, request)
+		suite.Require().NoError(err)
+
+		if i != 3 {
+			_, err = suite.repo.Update(ctx, usecase.FlatUpdateRequest{ID: flat.ID, Status: "approved"})
+			suite.Require().NoError(err)
+		}
+	}
+
+	response, err := suite.repo.Search(ctx, usecase.FlatSearchRequest{ApprovedOnly: true, Sort: "price_desc"})
+	suite.Require().NoError(err)
+	suite.Require().EqualValues(3, response.Total)
+	suite.Require().Len(response.Flats, 3)
+	suite.Require().EqualValues(300000, response.Flats[0].Price)
+
+	response, err = suite.repo.Search(ctx, usecase.FlatSearchRequest{Rooms: 2, YearFrom: 2000})
+	suite.Require().NoError(err)
+	suite.Require().EqualValues(1, response.Total)
+	suite.Require().EqualValues(300000, response.Flats[0].Price)
+
+	response, err = suite.repo.Search(ctx, usecase.FlatSearchRequest{PriceFrom: 150000, Sort: "price_asc", Limit: 2, Offset: 1})
+	suite.Require().NoError(err)
+	suite.Require().EqualValues(3, response.Total)
+	suite.Require().Len(response.Flats, 2)
+	suite.Require().EqualValues(300000, response.Flats[0].Price)
+	suite.Require().EqualValues(400000, response.Flats[1].Price)
+}
+
+func (suite *flatRepoSuite) TestBatchReads() {
+	ctx := context.Background()
+	firstHouseID := suite.insertTestHouse(ctx, "123 Test Street", 1990)
+	secondHouseID := suite.insertTestHouse(ctx, "456 Test Street", 2022)
+
+	first, err := suite.repo.Create(ctx, usecase.FlatCreateRequest{Number: 1, HouseID: firstHouseID, Price: 100000, Rooms: 1})
+	suite.Require().NoError(err)
+	_, err = suite.repo.Create(ctx, usecase.FlatCreateRequest{Number: 1, HouseID: secondHouseID, Price: 200000, Rooms: 2})
+	suite.Require().NoError(err)
+	_, err = suite.repo.Update(ctx, usecase.FlatUpdateRequest{ID: first.ID, Status: "approved"})
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.Create(ctx, usecase.FlatCreateRequest{Number: 2, HouseID: secondHouseID, Price: 300000, Rooms: 3})
+	suite.Require().NoError(err)
+
+	flats, err := suite.repo.FlatsByHouse(ctx, []int{firstHouseID, secondHouseID}, false, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(flats, 3)
+
+	flats, err = suite.repo.FlatsByHouse(ctx, []int{firstHouseID, secondHouseID}, false, 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(flats, 2)
+	suite.Require().Equal([]int{firstHouseID, secondHouseID}, []int{flats[0].HouseID, flats[1].HouseID})
+	suite.Require().Equal(1, flats[1].Number)
+
+	flats, err = suite.repo.FlatsByHouse(ctx, []int{firstHouseID, secondHouseID}, true, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(flats, 1)
+	suite.Require().Equal(first.ID, flats[0].ID)
+
+	flats, err = suite.repo.FlatsByStatus(ctx, "created")
+	suite.Require().NoError(err)
+	suite.Require().Len(flats, 1)
+	suite.Require().Equal(secondHouseID, flats[0].HouseID)
+
+	flats, err = suite.repo.FlatsByID(ctx, []int{first.ID, 99999999})
+	suite.Require().NoError(err)
+	suite.Require().Len(flats, 1)
+
+	moderations, err := suite.repo.ModerationsByFlat(ctx, []int{first.ID})
+	suite.Require().NoError(err)
+	suite.Require().Len(moderations, 1)
+	suite.Require().Equal("created", moderations[0].FromStatus)
+	suite.Require().Equal("approved", moderations[0].ToStatus)
+	suite.Require().Zero(moderations[0].ModeratorID)
+}
+
 func (suite *flatRepoSuite) insertTestHouse(ctx context.Context, address string, year int) int {
 	var houseID int
 	err := suite.db.ExecQueryRow(ctx, `
@@ -136,7 +266,7 @@ func (suite *flatRepoSuite) insertTestHouse(ctx context.Context, address string,
 
 func (suite *flatRepoSuite) clearTestDB(db *postgres.Database) {
 	ctx := context.Background()
-	tables := []string{"house", "flat"} // Укажите все таблицы, которые нужно очистить
+	tables := []string{"house", "flat", "flat_moderation", "notification"} // Укажите все таблицы, которые нужно очистить
 	_, err := db.Exec(ctx, "SET session_replication_role = 'replica'")
 	suite.Require().NoError(err)
 
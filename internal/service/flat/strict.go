@@ -0,0 +1,72 @@
+package flat
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+// The methods below implement the flat part of api.StrictServerInterface for /v2.
+
+func (h *Handler) CreateFlat(ctx context.Context, request api.CreateFlatRequestObject) (api.CreateFlatResponseObject, error) {
+	req := usecase.FlatCreateRequest{
+		Number:  request.Body.Number,
+		HouseID: request.Body.HouseId,
+		Price:   request.Body.Price,
+		Rooms:   request.Body.Rooms,
+	}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	response, err := h.repo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.CreateFlat200JSONResponse(response.API()), nil
+}
+
+func (h *Handler) UpdateFlat(ctx context.Context, request api.UpdateFlatRequestObject) (api.UpdateFlatResponseObject, error) {
+	req := usecase.FlatUpdateRequest{ID: request.Body.Id, Status: string(request.Body.Status)}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+	if claims, ok := ctx.Value("claims").(*auth.Claims); ok {
+		req.ModeratorID = claims.UserID
+	}
+
+	response, err := h.repo.Update(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.UpdateFlat200JSONResponse(response.API()), nil
+}
+
+func (h *Handler) SearchFlats(ctx context.Context, request api.SearchFlatsRequestObject) (api.SearchFlatsResponseObject, error) {
+	claims, ok := ctx.Value("claims").(*auth.Claims)
+	if !ok {
+		return nil, apierror.ErrMissingClaims
+	}
+
+	req := usecase.NewFlatSearchRequest(request.Params)
+	req.ApprovedOnly = !claims.Can(usecase.PermFlatModerate)
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	response, err := h.repo.Search(ctx, req)
+	if err != nil {
+		return nil, apierror.Internal("Failed to search flats", err)
+	}
+
+	return api.SearchFlats200JSONResponse{
+		Flats:  usecase.APIFlats(response.Flats),
+		Total:  response.Total,
+		Limit:  response.Limit,
+		Offset: response.Offset,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package house
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type cursor struct {
+	Value int
+	ID    int
+}
+
+func encodeCursor(sort, order string, c cursor) string {
+	raw := fmt.Sprintf("%s:%s:%d:%d", sort, order, c.Value, c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s, sort, order string) (cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 || parts[0] != sort || parts[1] != order {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	var c cursor
+	if c.Value, err = strconv.Atoi(parts[2]); err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	if c.ID, err = strconv.Atoi(parts[3]); err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	return c, nil
+}
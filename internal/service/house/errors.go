@@ -0,0 +1,14 @@
+package house
+
+import (
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"net/http"
+)
+
+var ErrInvalidCursor = errors.New("cursor is invalid or does not match the requested sort")
+
+// Errors maps the errors of the package to their API representation.
+var Errors = apierror.Mapper{
+	apierror.Map(ErrInvalidCursor, http.StatusBadRequest, apierror.CodeInvalidCursor, "Invalid cursor"),
+}
@@ -3,78 +3,153 @@ package house
 import (
 	"context"
 	"encoding/json"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
-	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
 	"net/http"
 	"strconv"
 )
 
 type House interface {
 	Create(ctx context.Context, house usecase.HouseCreateRequest) (usecase.House, error)
-	ClientFlats(ctx context.Context, houseID int) ([]usecase.FlatResponse, error)
-	ModeratorFlats(ctx context.Context, houseID int) ([]usecase.FlatResponse, error)
+	ClientFlats(ctx context.Context, houseID int, request usecase.HouseFlatsRequest) (usecase.HouseFlats, error)
+	ModeratorFlats(ctx context.Context, houseID int, request usecase.HouseFlatsRequest) (usecase.HouseFlats, error)
+	Search(ctx context.Context, request usecase.HouseSearchRequest) ([]usecase.House, error)
 }
 
 type Handler struct {
 	repo House
 }
 
-func NewHandler(db *postgres.Database) *Handler {
-	return &Handler{NewRepo(db)}
+func NewHandler(repo House) *Handler {
+	return &Handler{repo: repo}
 }
 
 func (h Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var req usecase.HouseCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		Errors.Write(w, r, apierror.ErrInvalidPayload)
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		Errors.Write(w, r, apierror.Validation(err))
 		return
 	}
 
 	response, err := h.repo.Create(r.Context(), req)
 	if err != nil {
-		http.Error(w, "Failed to create house", http.StatusInternalServerError)
+		Errors.Write(w, r, apierror.Internal("Failed to create house", err))
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err = json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to create house", http.StatusInternalServerError)
+		Errors.Write(w, r, apierror.Internal("Failed to create house", err))
 	}
 }
 
 func (h Handler) Flats(w http.ResponseWriter, r *http.Request) {
+	response, ok := h.flats(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		Errors.Write(w, r, err)
+	}
+}
+
+func (h Handler) flats(w http.ResponseWriter, r *http.Request) (usecase.HouseFlats, bool) {
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		http.Error(w, "id must be integer", http.StatusBadRequest)
-		return
+		Errors.Write(w, r, apierror.InvalidParameter("id must be integer"))
+		return usecase.HouseFlats{}, false
 	}
 
 	claims, ok := r.Context().Value("claims").(*auth.Claims)
 	if !ok {
-		http.Error(w, "Invalid or missing user claims", http.StatusUnauthorized)
-		return
+		Errors.Write(w, r, apierror.ErrMissingClaims)
+		return usecase.HouseFlats{}, false
+	}
+
+	query := r.URL.Query()
+	req := usecase.HouseFlatsRequest{
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+		Order:  query.Get("order"),
+		Status: query.Get("status"),
+	}
+
+	params := []struct {
+		name  string
+		value *int
+	}{
+		{"limit", &req.Limit},
+		{"rooms", &req.Rooms},
+		{"price_from", &req.PriceFrom},
+		{"price_to", &req.PriceTo},
+	}
+	for _, param := range params {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+
+		if *param.value, err = strconv.Atoi(raw); err != nil {
+			Errors.Write(w, r, apierror.InvalidParameter(param.name+" must be integer"))
+			return usecase.HouseFlats{}, false
+		}
+	}
+
+	if err = req.Validate(); err != nil {
+		Errors.Write(w, r, apierror.Validation(err))
+		return usecase.HouseFlats{}, false
 	}
 
-	var response []usecase.FlatResponse
-	role := claims.Role
-	if role == moderator {
-		response, err = h.repo.ModeratorFlats(r.Context(), id)
+	var response usecase.HouseFlats
+	if claims.Can(usecase.PermFlatModerate) {
+		response, err = h.repo.ModeratorFlats(r.Context(), id, req)
 	} else {
-		response, err = h.repo.ClientFlats(r.Context(), id)
+		response, err = h.repo.ClientFlats(r.Context(), id, req)
+	}
+
+	if err != nil {
+		Errors.Write(w, r, err)
+		return usecase.HouseFlats{}, false
+	}
+
+	if response.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", response.NextCursor)
+	}
+
+	return response, true
+}
+
+func (h Handler) Search(w http.ResponseWriter, r *http.Request) {
+	req := usecase.HouseSearchRequest{Query: r.URL.Query().Get("q")}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		var err error
+		if req.Limit, err = strconv.Atoi(limit); err != nil {
+			Errors.Write(w, r, apierror.InvalidParameter("limit must be integer"))
+			return
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		Errors.Write(w, r, apierror.Validation(err))
+		return
 	}
 
+	houses, err := h.repo.Search(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		Errors.Write(w, r, apierror.Internal("Failed to search houses", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err = json.NewEncoder(w).Encode(usecase.HouseFlats{Flat: response}); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err = json.NewEncoder(w).Encode(usecase.HouseSearchResponse{Houses: houses}); err != nil {
+		Errors.Write(w, r, apierror.Internal("Failed to search houses", err))
 	}
 }
@@ -5,22 +5,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/flat"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
-	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
-	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/suite"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"strconv"
 	"testing"
+	"time"
 )
 
 type houseHandlerSuite struct {
 	suite.Suite
-	db      *postgres.Database
 	auth    *auth.Handler
 	flat    *flat.Handler
 	handler *Handler
@@ -30,22 +30,13 @@ func TestHouseHandlerSuite(t *testing.T) {
 	suite.Run(t, new(houseHandlerSuite))
 }
 
-func (suite *houseHandlerSuite) SetupSuite() {
-	db, err := postgres.NewDB(context.Background(), suite.fromEnv())
-	suite.Require().NoError(err)
-
-	suite.db = db
-	suite.auth = auth.NewHandler(db)
-	suite.flat = flat.NewHandler(db)
-	suite.handler = NewHandler(db)
-}
-
-func (suite *houseHandlerSuite) TearDownSuite() {
-	suite.db.GetPool().Close()
-}
-
 func (suite *houseHandlerSuite) SetupTest() {
-	suite.clearTestDB(suite.db)
+	store := memory.NewStore()
+	tokens := auth.NewTokenManager(auth.Config{Secret: "test-secret", TokenTTL: time.Hour, Issuer: "test"})
+
+	suite.auth = auth.NewHandler(auth.NewMemoryRepo(store, tokens), tokens)
+	suite.flat = flat.NewHandler(flat.NewMemoryRepo(store))
+	suite.handler = NewHandler(NewMemoryRepo(store))
 }
 
 func (suite *houseHandlerSuite) TestCreateHouseFailDecoding() {
@@ -174,8 +165,9 @@ func (suite *houseHandlerSuite) TestClientFlats() {
 	listRecorder := httptest.NewRecorder()
 
 	claims := &auth.Claims{
-		UserID: 1,
-		Role:   "client",
+		UserID:      1,
+		Role:        "client",
+		Permissions: usecase.DefaultRoles["client"],
 	}
 	ctx := context.WithValue(listReq.Context(), "claims", claims)
 	listReq = listReq.WithContext(ctx)
@@ -237,8 +229,9 @@ func (suite *houseHandlerSuite) TestModeratorFlats() {
 	listRecorder := httptest.NewRecorder()
 
 	claims := &auth.Claims{
-		UserID: 1,
-		Role:   "moderator",
+		UserID:      1,
+		Role:        "moderator",
+		Permissions: usecase.DefaultRoles["moderator"],
 	}
 	ctx := context.WithValue(listReq.Context(), "claims", claims)
 	listReq = listReq.WithContext(ctx)
@@ -253,6 +246,94 @@ func (suite *houseHandlerSuite) TestModeratorFlats() {
 	suite.Require().Len(flatsList.Flat, 2)
 }
 
+func (suite *houseHandlerSuite) TestListHouseFlatsShape() {
+	houseID := suite.createHouse()
+
+	ctx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: 1, Role: "moderator", Permissions: usecase.DefaultRoles["moderator"]})
+	response, err := suite.handler.ListHouseFlats(ctx, api.ListHouseFlatsRequestObject{Id: houseID})
+	suite.Require().NoError(err)
+
+	w := httptest.NewRecorder()
+	suite.Require().NoError(response.VisitListHouseFlatsResponse(w))
+	suite.Require().EqualValues(http.StatusOK, w.Code)
+	suite.Require().JSONEq(`{"flats": []}`, w.Body.String())
+}
+
+func (suite *houseHandlerSuite) TestFlatsDefaultLimit() {
+	houseID := suite.createHouse()
+	ctx := context.Background()
+	for number := 1; number <= usecase.DefaultHouseFlatsLimit+1; number++ {
+		_, err := suite.flat.CreateFlat(ctx, api.CreateFlatRequestObject{Body: &api.CreateFlatJSONRequestBody{HouseId: houseID, Number: number, Price: 1000, Rooms: 1}})
+		suite.Require().NoError(err)
+	}
+	claims := &auth.Claims{UserID: 1, Role: "moderator", Permissions: usecase.DefaultRoles["moderator"]}
+
+	// v1 clients don't read the cursor and get every flat
+	req := httptest.NewRequest(http.MethodGet, "/house/", nil)
+	req.SetPathValue("id", strconv.Itoa(houseID))
+	req = req.WithContext(context.WithValue(req.Context(), "claims", claims))
+	w := httptest.NewRecorder()
+	suite.handler.Flats(w, req)
+	suite.Require().EqualValues(http.StatusOK, w.Code)
+	suite.Require().Empty(w.Header().Get("X-Next-Cursor"))
+
+	var flats usecase.HouseFlats
+	suite.Require().NoError(json.NewDecoder(w.Body).Decode(&flats))
+	suite.Require().Len(flats.Flat, usecase.DefaultHouseFlatsLimit+1)
+
+	response, err := suite.handler.ListHouseFlats(context.WithValue(ctx, "claims", claims), api.ListHouseFlatsRequestObject{Id: houseID})
+	suite.Require().NoError(err)
+	page := response.(listHouseFlatsResponse)
+	suite.Require().Len(page.Body.Flats, usecase.DefaultHouseFlatsLimit)
+	suite.Require().NotEmpty(page.Headers.XNextCursor)
+}
+
+func (suite *houseHandlerSuite) TestListHouseFlatsMissingClaims() {
+	_, err := suite.handler.ListHouseFlats(context.Background(), api.ListHouseFlatsRequestObject{Id: suite.createHouse()})
+	suite.Require().Equal(apierror.ErrMissingClaims, err)
+}
+
+func (suite *houseHandlerSuite) TestFlatsInvalidCursor() {
+	houseID := suite.createHouse()
+
+	req := httptest.NewRequest(http.MethodGet, "/house/?cursor=garbage", nil)
+	req.SetPathValue("id", strconv.Itoa(houseID))
+	req = req.WithContext(context.WithValue(req.Context(), "claims", &auth.Claims{UserID: 1, Role: "moderator", Permissions: usecase.DefaultRoles["moderator"]}))
+
+	w := httptest.NewRecorder()
+	suite.handler.Flats(w, req)
+
+	suite.Require().EqualValues(http.StatusBadRequest, w.Code)
+	suite.Require().Contains(w.Body.String(), "Invalid cursor")
+}
+
+func (suite *houseHandlerSuite) TestSearchMissingQuery() {
+	req := httptest.NewRequest(http.MethodGet, "/house/search", nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Search(w, req)
+
+	suite.Require().EqualValues(http.StatusBadRequest, w.Code)
+	suite.Require().Contains(w.Body.String(), "Field validation for 'Query' failed on the 'required' tag")
+}
+
+func (suite *houseHandlerSuite) TestSearchSuccess() {
+	houseID := suite.createHouse()
+
+	req := httptest.NewRequest(http.MethodGet, "/house/search?q=садовой&limit=5", nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Search(w, req)
+
+	suite.Require().EqualValues(http.StatusOK, w.Code)
+
+	var response usecase.HouseSearchResponse
+	err := json.NewDecoder(w.Body).Decode(&response)
+	suite.Require().NoError(err)
+	suite.Require().Len(response.Houses, 1)
+	suite.Require().EqualValues(houseID, response.Houses[0].ID)
+}
+
 func (suite *houseHandlerSuite) createHouse() int {
 	validRequest := `{
 		"address": "Садовая улица, 15, Москва, 123456",
@@ -304,33 +385,3 @@ func (suite *houseHandlerSuite) moderatorToken() string {
 	suite.Require().NoError(err)
 	return loginResponse.Token
 }
-
-func (suite *houseHandlerSuite) clearTestDB(db *postgres.Database) {
-	ctx := context.Background()
-	tables := []string{`"user"`, "flat", "house"}
-	_, err := db.Exec(ctx, "SET session_replication_role = 'replica'")
-	suite.Require().NoError(err)
-
-	for _, table := range tables {
-		_, err := db.Exec(ctx, fmt.Sprintf("TRUNCATE %s CASCADE", table))
-		suite.Require().NoError(err)
-	}
-
-	_, err = db.Exec(ctx, "SET session_replication_role = 'origin'")
-	suite.Require().NoError(err)
-
-	fmt.Println("Test database cleared successfully")
-}
-
-func (suite *houseHandlerSuite) fromEnv() postgres.DatabaseConfig {
-	err := godotenv.Load("../../../.env")
-	suite.Require().NoError(err)
-
-	return postgres.DatabaseConfig{
-		Host:     os.Getenv("DB_HOST"),
-		Port:     os.Getenv("DB_PORT"),
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		Name:     os.Getenv("DB_NAME"),
-	}
-}
@@ -0,0 +1,190 @@
+package house
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	_ House       = (*MemoryRepo)(nil)
+	_ BatchReader = (*MemoryRepo)(nil)
+)
+
+// MemoryRepo is a House backed by memory.Store, used by tests that don't need PostgreSQL.
+type MemoryRepo struct {
+	store *memory.Store
+}
+
+func NewMemoryRepo(store *memory.Store) *MemoryRepo {
+	return &MemoryRepo{
+		store: store,
+	}
+}
+
+func (repo *MemoryRepo) Create(ctx context.Context, house usecase.HouseCreateRequest) (usecase.House, error) {
+	repo.store.Lock()
+	defer repo.store.Unlock()
+
+	now := time.Now()
+	response := usecase.House{
+		ID:        repo.store.NextID("house"),
+		Address:   house.Address,
+		Year:      house.Year,
+		Developer: house.Developer,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	repo.store.Houses[response.ID] = response
+
+	return response, nil
+}
+
+func (repo *MemoryRepo) ClientFlats(ctx context.Context, houseID int, request usecase.HouseFlatsRequest) (usecase.HouseFlats, error) {
+	return repo.flats(houseID, approved, request)
+}
+
+func (repo *MemoryRepo) ModeratorFlats(ctx context.Context, houseID int, request usecase.HouseFlatsRequest) (usecase.HouseFlats, error) {
+	return repo.flats(houseID, request.Status, request)
+}
+
+func (repo *MemoryRepo) flats(houseID int, status string, request usecase.HouseFlatsRequest) (usecase.HouseFlats, error) {
+	response := usecase.HouseFlats{Flat: make([]usecase.FlatResponse, 0)}
+
+	sort, order, limit := request.Sort, request.Order, request.Limit
+	if sort == "" {
+		sort = "number"
+	}
+	if order == "" {
+		order = "asc"
+	}
+
+	var after cursor
+	if request.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(request.Cursor, sort, order); err != nil {
+			return response, err
+		}
+	}
+
+	// compare orders flats by (sort value, id) in the requested direction
+	compare := func(value, id int, than cursor) int {
+		result := value - than.Value
+		if result == 0 {
+			result = id - than.ID
+		}
+		if order == "desc" {
+			result = -result
+		}
+		return result
+	}
+
+	repo.store.RLock()
+	for _, flat := range repo.store.Flats {
+		switch {
+		case flat.HouseID != houseID,
+			status != "" && flat.Status != status,
+			request.Rooms != 0 && flat.Rooms != request.Rooms,
+			request.PriceFrom != 0 && flat.Price < request.PriceFrom,
+			request.PriceTo != 0 && flat.Price > request.PriceTo,
+			request.Cursor != "" && compare(sortValue(flat.FlatResponse, sort), flat.ID, after) <= 0:
+			continue
+		}
+		response.Flat = append(response.Flat, flat.FlatResponse)
+	}
+	repo.store.RUnlock()
+
+	slices.SortFunc(response.Flat, func(a, b usecase.FlatResponse) int {
+		return compare(sortValue(a, sort), a.ID, cursor{Value: sortValue(b, sort), ID: b.ID})
+	})
+
+	if limit > 0 && len(response.Flat) > limit {
+		response.Flat = response.Flat[:limit]
+		last := response.Flat[limit-1]
+		response.NextCursor = encodeCursor(sort, order, cursor{Value: sortValue(last, sort), ID: last.ID})
+	}
+
+	return response, nil
+}
+
+// Search approximates the PostgreSQL full-text search: a house matches when a
+// query word shares a stem with a word of its address or developer, and houses
+// with more matching words come first.
+func (repo *MemoryRepo) Search(ctx context.Context, request usecase.HouseSearchRequest) ([]usecase.House, error) {
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	type ranked struct {
+		house usecase.House
+		rank  int
+	}
+
+	query := words(request.Query)
+	matches := make([]ranked, 0)
+
+	repo.store.RLock()
+	for _, house := range repo.store.Houses {
+		text := words(house.Address + " " + house.Developer)
+		rank := 0
+		for _, q := range query {
+			if slices.ContainsFunc(text, func(word string) bool { return sameStem(q, word) }) {
+				rank++
+			}
+		}
+		if rank > 0 {
+			matches = append(matches, ranked{house: house, rank: rank})
+		}
+	}
+	repo.store.RUnlock()
+
+	slices.SortFunc(matches, func(a, b ranked) int {
+		if a.rank != b.rank {
+			return b.rank - a.rank
+		}
+		return a.house.ID - b.house.ID
+	})
+
+	houses := make([]usecase.House, 0, min(len(matches), limit))
+	for _, match := range matches[:min(len(matches), limit)] {
+		houses = append(houses, match.house)
+	}
+
+	return houses, nil
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// sameStem treats words as equal when they differ only in the last two letters,
+// which covers most Russian inflections ("садовая" and "садовой").
+func sameStem(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	n := max(min(len(ra), len(rb))-2, 3)
+	if len(ra) < n || len(rb) < n {
+		return a == b
+	}
+	return string(ra[:n]) == string(rb[:n])
+}
+
+func (repo *MemoryRepo) HousesByID(ctx context.Context, ids []int) ([]usecase.House, error) {
+	repo.store.RLock()
+	defer repo.store.RUnlock()
+
+	houses := make([]usecase.House, 0, len(ids))
+	for _, id := range ids {
+		if house, ok := repo.store.Houses[id]; ok {
+			houses = append(houses, house)
+		}
+	}
+
+	return houses, nil
+}
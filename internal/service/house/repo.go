@@ -2,11 +2,24 @@ package house
 
 import (
 	"context"
+	"fmt"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
 	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
 )
 
-const moderator = "moderator"
+const (
+	approved = "approved"
+
+	defaultSearchLimit = 20
+)
+
+var _ BatchReader = (*Repo)(nil)
+
+// BatchReader loads many houses at once, so that GraphQL resolvers can batch
+// their lookups.
+type BatchReader interface {
+	HousesByID(ctx context.Context, ids []int) ([]usecase.House, error)
+}
 
 type Repo struct {
 	db *postgres.Database
@@ -24,10 +37,10 @@ func (repo *Repo) Create(ctx context.Context, house usecase.HouseCreateRequest)
 	query := `
 		INSERT INTO house (address, year, developer) 
 		VALUES ($1, $2, $3) 
-		RETURNING *
+		RETURNING id, address, year, developer, created_at, updated_at
 	`
 
-	err := repo.db.Get(ctx, &response, query, house.Address, house.Year, house.Developer)
+	err := repo.db.Get(postgres.WithPrimary(ctx), &response, query, house.Address, house.Year, house.Developer)
 	if err != nil {
 		return response, err
 	}
@@ -35,34 +48,119 @@ func (repo *Repo) Create(ctx context.Context, house usecase.HouseCreateRequest)
 	return response, nil
 }
 
-func (repo *Repo) ClientFlats(ctx context.Context, houseID int) ([]usecase.FlatResponse, error) {
-	query := `
+func (repo *Repo) ClientFlats(ctx context.Context, houseID int, request usecase.HouseFlatsRequest) (usecase.HouseFlats, error) {
+	return repo.flats(ctx, houseID, approved, request)
+}
+
+func (repo *Repo) ModeratorFlats(ctx context.Context, houseID int, request usecase.HouseFlatsRequest) (usecase.HouseFlats, error) {
+	return repo.flats(ctx, houseID, request.Status, request)
+}
+
+func (repo *Repo) flats(ctx context.Context, houseID int, status string, request usecase.HouseFlatsRequest) (usecase.HouseFlats, error) {
+	response := usecase.HouseFlats{Flat: make([]usecase.FlatResponse, 0)}
+
+	sort, order, limit := request.Sort, request.Order, request.Limit
+	if sort == "" {
+		sort = "number"
+	}
+	if order == "" {
+		order = "asc"
+	}
+
+	// LIMIT NULL returns every flat
+	var fetch any
+	if limit > 0 {
+		fetch = limit + 1
+	}
+
+	var after cursor
+	if request.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(request.Cursor, sort, order); err != nil {
+			return response, err
+		}
+	}
+
+	comparator := ">"
+	if order == "desc" {
+		comparator = "<"
+	}
+
+	query := fmt.Sprintf(`
 		SELECT id, number, house_id, price, rooms, status
 		FROM flat
-		WHERE house_id = $1 AND status = 'approved'
-	`
+		WHERE house_id = $1
+			AND ($2 = '' OR status = $2)
+			AND ($3 = 0 OR rooms = $3)
+			AND ($4 = 0 OR price >= $4)
+			AND ($5 = 0 OR price <= $5)
+			AND ($6 = false OR (%[1]s, id) %[2]s ($7, $8))
+		ORDER BY %[1]s %[3]s, id %[3]s
+		LIMIT $9
+	`, sort, comparator, order)
 
-	var flats []usecase.FlatResponse
-	err := repo.db.Select(ctx, &flats, query, houseID)
+	err := repo.db.Select(ctx, &response.Flat, query,
+		houseID, status, request.Rooms, request.PriceFrom, request.PriceTo,
+		request.Cursor != "", after.Value, after.ID, fetch)
 	if err != nil {
-		return flats, err
+		return response, err
+	}
+
+	if limit > 0 && len(response.Flat) > limit {
+		response.Flat = response.Flat[:limit]
+		last := response.Flat[limit-1]
+		response.NextCursor = encodeCursor(sort, order, cursor{Value: sortValue(last, sort), ID: last.ID})
 	}
 
-	return flats, nil
+	return response, nil
 }
 
-func (repo *Repo) ModeratorFlats(ctx context.Context, houseID int) ([]usecase.FlatResponse, error) {
+func sortValue(flat usecase.FlatResponse, sort string) int {
+	switch sort {
+	case "price":
+		return flat.Price
+	case "rooms":
+		return flat.Rooms
+	default:
+		return flat.Number
+	}
+}
+
+func (repo *Repo) Search(ctx context.Context, request usecase.HouseSearchRequest) ([]usecase.House, error) {
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
 	query := `
-		SELECT id, number, house_id, price, rooms, status
-		FROM flat
-		WHERE house_id = $1
+		SELECT id, address, year, coalesce(developer, '') AS developer, created_at, updated_at
+		FROM house, websearch_to_tsquery('russian', $1) AS query
+		WHERE search_vector @@ query
+			OR $1 <% address
+			OR $1 <% developer
+		ORDER BY ts_rank(search_vector, query)
+			+ greatest(word_similarity($1, address), word_similarity($1, coalesce(developer, ''))) DESC, id
+		LIMIT $2
 	`
 
-	var flats []usecase.FlatResponse
-	err := repo.db.Select(ctx, &flats, query, houseID)
+	houses := make([]usecase.House, 0)
+	err := repo.db.Select(ctx, &houses, query, request.Query, limit)
 	if err != nil {
-		return flats, err
+		return houses, err
 	}
 
-	return flats, nil
+	return houses, nil
+}
+
+// HousesByID returns the houses with the given ids, missing ids are skipped.
+func (repo *Repo) HousesByID(ctx context.Context, ids []int) ([]usecase.House, error) {
+	query := `
+		SELECT id, address, year, coalesce(developer, '') AS developer, created_at, updated_at
+		FROM house
+		WHERE id = ANY($1)
+	`
+
+	houses := make([]usecase.House, 0, len(ids))
+	err := repo.db.Select(ctx, &houses, query, ids)
+	return houses, err
 }
@@ -24,6 +24,10 @@ func TestHouseRepoSuite(t *testing.T) {
 func (suite *houseRepoSuite) SetupSuite() {
 	db, err := postgres.NewDB(context.Background(), suite.fromEnv())
 	suite.Require().NoError(err)
+	if err = db.GetPool().Ping(context.Background()); err != nil {
+		db.Close()
+		suite.T().Skipf("PostgreSQL is not available: %v", err)
+	}
 
 	suite.db = db
 	suite.repo = NewRepo(suite.db)
@@ -62,11 +66,11 @@ func (suite *houseRepoSuite) TestClientFlatsSuccessful() {
 	suite.insertTestFlat(ctx, house.ID, 1, 100000, 3, "approved")
 	suite.insertTestFlat(ctx, house.ID, 2, 150000, 2, "created")
 
-	flats, err := suite.repo.ClientFlats(ctx, house.ID)
+	flats, err := suite.repo.ClientFlats(ctx, house.ID, usecase.HouseFlatsRequest{})
 	suite.Require().NoError(err)
-	suite.Require().Len(flats, 1)
-	suite.Require().Equal(1, flats[0].Number)
-	suite.Require().Equal("approved", flats[0].Status)
+	suite.Require().Len(flats.Flat, 1)
+	suite.Require().Equal(1, flats.Flat[0].Number)
+	suite.Require().Equal("approved", flats.Flat[0].Status)
 
 }
 
@@ -83,9 +87,78 @@ func (suite *houseRepoSuite) TestModeratorFlatsSuccessful() {
 	suite.insertTestFlat(ctx, house.ID, 1, 100000, 3, "approved")
 	suite.insertTestFlat(ctx, house.ID, 2, 150000, 2, "created")
 
-	flats, err := suite.repo.ModeratorFlats(ctx, house.ID)
+	flats, err := suite.repo.ModeratorFlats(ctx, house.ID, usecase.HouseFlatsRequest{})
+	suite.Require().NoError(err)
+	suite.Require().Len(flats.Flat, 2)
+}
+
+func (suite *houseRepoSuite) TestModeratorFlatsPagination() {
+	ctx := context.Background()
+	house, err := suite.repo.Create(ctx, usecase.HouseCreateRequest{Address: "someAddress4", Year: 2000})
+	suite.Require().NoError(err)
+
+	suite.insertTestFlat(ctx, house.ID, 1, 300000, 3, "approved")
+	suite.insertTestFlat(ctx, house.ID, 2, 100000, 1, "created")
+	suite.insertTestFlat(ctx, house.ID, 3, 200000, 2, "approved")
+	suite.insertTestFlat(ctx, house.ID, 4, 400000, 4, "declined")
+
+	request := usecase.HouseFlatsRequest{Sort: "price", Order: "desc", Limit: 3}
+	page, err := suite.repo.ModeratorFlats(ctx, house.ID, request)
+	suite.Require().NoError(err)
+	suite.Require().Len(page.Flat, 3)
+	suite.Require().Equal([]int{4, 1, 3}, []int{page.Flat[0].Number, page.Flat[1].Number, page.Flat[2].Number})
+	suite.Require().NotEmpty(page.NextCursor)
+
+	request.Cursor = page.NextCursor
+	page, err = suite.repo.ModeratorFlats(ctx, house.ID, request)
+	suite.Require().NoError(err)
+	suite.Require().Len(page.Flat, 1)
+	suite.Require().Equal(2, page.Flat[0].Number)
+	suite.Require().Empty(page.NextCursor)
+
+	request.Sort = "rooms"
+	_, err = suite.repo.ModeratorFlats(ctx, house.ID, request)
+	suite.Require().ErrorIs(err, ErrInvalidCursor)
+
+	page, err = suite.repo.ModeratorFlats(ctx, house.ID, usecase.HouseFlatsRequest{Status: "approved", PriceFrom: 250000})
+	suite.Require().NoError(err)
+	suite.Require().Len(page.Flat, 1)
+	suite.Require().Equal(1, page.Flat[0].Number)
+}
+
+func (suite *houseRepoSuite) TestSearchWordForms() {
+	ctx := context.Background()
+	_, err := suite.repo.Create(ctx, usecase.HouseCreateRequest{
+		Address:   "Лесная улица, 7, Москва, 125196",
+		Year:      2000,
+		Developer: "Мэрия города",
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.Create(ctx, usecase.HouseCreateRequest{
+		Address: "Садовая улица, 15, Москва, 123456",
+		Year:    2010,
+	})
+	suite.Require().NoError(err)
+
+	houses, err := suite.repo.Search(ctx, usecase.HouseSearchRequest{Query: "на лесной улице"})
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(houses)
+	suite.Require().Equal("Лесная улица, 7, Москва, 125196", houses[0].Address)
+}
+
+func (suite *houseRepoSuite) TestSearchTypo() {
+	ctx := context.Background()
+	_, err := suite.repo.Create(ctx, usecase.HouseCreateRequest{
+		Address: "Лесная улица, 7, Москва, 125196",
+		Year:    2000,
+	})
+	suite.Require().NoError(err)
+
+	houses, err := suite.repo.Search(ctx, usecase.HouseSearchRequest{Query: "Лесная улца"})
 	suite.Require().NoError(err)
-	suite.Require().Len(flats, 2)
+	suite.Require().NotEmpty(houses)
+	suite.Require().Equal("Лесная улица, 7, Москва, 125196", houses[0].Address)
 }
 
 func (suite *houseRepoSuite) insertTestFlat(ctx context.Context, houseID, number, price, rooms int, status string) int {
@@ -101,7 +174,7 @@ func (suite *houseRepoSuite) insertTestFlat(ctx context.Context, houseID, number
 
 func (suite *houseRepoSuite) clearTestDB(db *postgres.Database) {
 	ctx := context.Background()
-	tables := []string{`"user"`}
+	tables := []string{`"user"`, "flat", "house"}
 	_, err := db.Exec(ctx, "SET session_replication_role = 'replica'")
 	suite.Require().NoError(err)
 
@@ -0,0 +1,88 @@
+package house
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/auth"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"net/http"
+)
+
+// The methods below implement the house part of api.StrictServerInterface for /v2.
+
+func (h Handler) CreateHouse(ctx context.Context, request api.CreateHouseRequestObject) (api.CreateHouseResponseObject, error) {
+	req := usecase.HouseCreateRequest{Address: request.Body.Address, Year: request.Body.Year}
+	if request.Body.Developer != nil {
+		req.Developer = *request.Body.Developer
+	}
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	response, err := h.repo.Create(ctx, req)
+	if err != nil {
+		return nil, apierror.Internal("Failed to create house", err)
+	}
+
+	return api.CreateHouse200JSONResponse(response.API()), nil
+}
+
+func (h Handler) SearchHouses(ctx context.Context, request api.SearchHousesRequestObject) (api.SearchHousesResponseObject, error) {
+	req := usecase.NewHouseSearchRequest(request.Params)
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	houses, err := h.repo.Search(ctx, req)
+	if err != nil {
+		return nil, apierror.Internal("Failed to search houses", err)
+	}
+
+	return api.SearchHouses200JSONResponse{Houses: usecase.APIHouses(houses)}, nil
+}
+
+func (h Handler) ListHouseFlats(ctx context.Context, request api.ListHouseFlatsRequestObject) (api.ListHouseFlatsResponseObject, error) {
+	claims, ok := ctx.Value("claims").(*auth.Claims)
+	if !ok {
+		return nil, apierror.ErrMissingClaims
+	}
+
+	req := usecase.NewHouseFlatsRequest(request.Params)
+	if err := req.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	var (
+		flats usecase.HouseFlats
+		err   error
+	)
+	if claims.Can(usecase.PermFlatModerate) {
+		flats, err = h.repo.ModeratorFlats(ctx, request.Id, req)
+	} else {
+		flats, err = h.repo.ClientFlats(ctx, request.Id, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response listHouseFlatsResponse
+	response.Body.Flats = usecase.APIFlats(flats.Flat)
+	response.Headers.XNextCursor = flats.NextCursor
+	return response, nil
+}
+
+// listHouseFlatsResponse leaves X-Next-Cursor out on the last page, the generated
+// response would send it empty.
+type listHouseFlatsResponse api.ListHouseFlats200JSONResponse
+
+func (response listHouseFlatsResponse) VisitListHouseFlatsResponse(w http.ResponseWriter) error {
+	if response.Headers.XNextCursor != "" {
+		return api.ListHouseFlats200JSONResponse(response).VisitListHouseFlatsResponse(w)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response.Body)
+}
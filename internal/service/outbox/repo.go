@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// Repo is the notification table, the outbox the sender worker delivers from.
+type Repo struct {
+	db *postgres.Database
+}
+
+func NewRepo(db *postgres.Database) *Repo {
+	return &Repo{
+		db: db,
+	}
+}
+
+// Enqueue adds message for every subscriber of the house in tx, so that it is
+// only sent if the change it announces is committed.
+func Enqueue(ctx context.Context, tx pgx.Tx, houseID int, message string) error {
+	query := `
+		INSERT INTO notification (email, message)
+		SELECT email, $2
+		FROM subscriber
+		WHERE house_id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, houseID, message)
+	return err
+}
+
+// Claim leases up to limit due notifications. Leased notifications are claimed
+// again once the lease expires, unless they are marked sent or failed before.
+func (repo *Repo) Claim(ctx context.Context, limit int, lease time.Duration) ([]usecase.Notification, error) {
+	query := `
+		UPDATE notification
+		SET status = 'processing',
+			attempts = attempts + 1,
+			next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id
+			FROM notification
+			WHERE status IN ('pending', 'processing') AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, email, message, attempts
+	`
+
+	notifications := make([]usecase.Notification, 0)
+	err := repo.db.Select(postgres.WithPrimary(ctx), &notifications, query, limit, lease.Seconds())
+	if err != nil {
+		return notifications, err
+	}
+
+	return notifications, nil
+}
+
+func (repo *Repo) MarkSent(ctx context.Context, id int) error {
+	query := `
+		UPDATE notification
+		SET status = 'sent', last_error = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	_, err := repo.db.Exec(ctx, query, id)
+	return err
+}
+
+// MarkFailed retries the notification after backoff times its attempts, or
+// gives up on it once it has been attempted maxAttempts times.
+func (repo *Repo) MarkFailed(ctx context.Context, id int, maxAttempts int, backoff time.Duration, cause error) error {
+	query := `
+		UPDATE notification
+		SET status = CASE WHEN attempts >= $2 THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $3::float8 * attempts),
+			last_error = $4,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	_, err := repo.db.Exec(ctx, query, id, maxAttempts, backoff.Seconds(), cause.Error())
+	return err
+}
+
+// Redrive makes failed notifications pending again with a fresh set of
+// attempts. With no ids every failed notification is redriven.
+func (repo *Repo) Redrive(ctx context.Context, ids []int) (int64, error) {
+	query := `
+		UPDATE notification
+		SET status = 'pending',
+			attempts = 0,
+			next_attempt_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'failed' AND (cardinality($1::bigint[]) = 0 OR id = ANY($1))
+	`
+
+	if ids == nil {
+		ids = []int{}
+	}
+	tag, err := repo.db.Exec(ctx, query, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
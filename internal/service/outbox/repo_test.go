@@ -0,0 +1,79 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"testing"
+	"time"
+)
+
+type outboxRepoSuite struct {
+	suite.Suite
+	db   *postgres.Database
+	repo *Repo
+}
+
+func TestOutboxRepoSuite(t *testing.T) {
+	suite.Run(t, new(outboxRepoSuite))
+}
+
+func (suite *outboxRepoSuite) SetupSuite() {
+	db, err := postgres.NewDB(context.Background(), suite.fromEnv())
+	suite.Require().NoError(err)
+	if err = db.GetPool().Ping(context.Background()); err != nil {
+		db.Close()
+		suite.T().Skipf("PostgreSQL is not available: %v", err)
+	}
+
+	suite.db = db
+	suite.repo = NewRepo(suite.db)
+}
+
+func (suite *outboxRepoSuite) TearDownSuite() {
+	suite.db.GetPool().Close()
+}
+
+func (suite *outboxRepoSuite) SetupTest() {
+	_, err := suite.db.Exec(context.Background(), "TRUNCATE notification")
+	suite.Require().NoError(err)
+}
+
+func (suite *outboxRepoSuite) TestMarkFailedKeepsSubSecondBackoff() {
+	ctx := context.Background()
+	var id int
+	err := suite.db.ExecQueryRow(ctx, `INSERT INTO notification (email, message) VALUES ('user@example.com', 'message') RETURNING id`).Scan(&id)
+	suite.Require().NoError(err)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		claimed, err := suite.repo.Claim(ctx, 1, 0)
+		suite.Require().NoError(err)
+		suite.Require().Len(claimed, 1)
+		suite.Require().NoError(suite.repo.MarkFailed(ctx, id, 5, 500*time.Millisecond, errors.New("unavailable")))
+
+		// Both timestamps are the CURRENT_TIMESTAMP of the same statement
+		var delay float64
+		err = suite.db.Get(ctx, &delay, `SELECT extract(epoch FROM next_attempt_at - updated_at)::float8 FROM notification WHERE id = $1`, id)
+		suite.Require().NoError(err)
+		suite.Require().InDelta(0.5*float64(attempt), delay, 1e-6)
+
+		_, err = suite.db.Exec(ctx, `UPDATE notification SET next_attempt_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *outboxRepoSuite) fromEnv() postgres.DatabaseConfig {
+	err := godotenv.Load("../../../.env")
+	suite.Require().NoError(err)
+
+	return postgres.DatabaseConfig{
+		Host:     os.Getenv("DB_HOST"),
+		Port:     os.Getenv("DB_PORT"),
+		User:     os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASSWORD"),
+		Name:     os.Getenv("DB_NAME"),
+	}
+}
@@ -1,5 +1,14 @@
 package sender
 
-import "errors"
+import (
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"net/http"
+)
 
 var ErrHouseNotFound = errors.New("house with the given ID does not exist")
+
+// Errors maps the errors of the package to their API representation.
+var Errors = apierror.Mapper{
+	apierror.Map(ErrHouseNotFound, http.StatusNotFound, apierror.CodeHouseNotFound, "House with this ID does not exist"),
+}
@@ -0,0 +1,53 @@
+package sender
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/memory"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+var (
+	_ Subscriber  = (*MemoryRepo)(nil)
+	_ BatchReader = (*MemoryRepo)(nil)
+)
+
+// MemoryRepo is a Subscriber backed by memory.Store, used by tests that don't need PostgreSQL.
+type MemoryRepo struct {
+	store *memory.Store
+}
+
+func NewMemoryRepo(store *memory.Store) *MemoryRepo {
+	return &MemoryRepo{
+		store: store,
+	}
+}
+
+func (repo *MemoryRepo) Subscribe(ctx context.Context, houseID int, subscriber usecase.Subscribe) error {
+	repo.store.Lock()
+	defer repo.store.Unlock()
+
+	if _, ok := repo.store.Houses[houseID]; !ok {
+		return ErrHouseNotFound
+	}
+
+	// Like ON CONFLICT (house_id) DO NOTHING, the first subscriber of a house stays
+	if _, ok := repo.store.Subscribers[houseID]; !ok {
+		repo.store.Subscribers[houseID] = subscriber.Email
+	}
+
+	return nil
+}
+
+func (repo *MemoryRepo) SubscriptionsByHouse(ctx context.Context, houseIDs []int) ([]usecase.Subscription, error) {
+	repo.store.RLock()
+	defer repo.store.RUnlock()
+
+	subscriptions := make([]usecase.Subscription, 0, len(houseIDs))
+	for _, houseID := range houseIDs {
+		if email, ok := repo.store.Subscribers[houseID]; ok {
+			subscriptions = append(subscriptions, usecase.Subscription{HouseID: houseID, Email: email})
+		}
+	}
+
+	return subscriptions, nil
+}
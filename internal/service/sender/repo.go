@@ -6,6 +6,14 @@ import (
 	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
 )
 
+var _ BatchReader = (*Repo)(nil)
+
+// BatchReader loads the subscriptions of many houses at once, so that GraphQL
+// resolvers can batch their lookups.
+type BatchReader interface {
+	SubscriptionsByHouse(ctx context.Context, houseIDs []int) ([]usecase.Subscription, error)
+}
+
 type Repo struct {
 	db *postgres.Database
 }
@@ -20,7 +28,7 @@ func (repo *Repo) Subscribe(ctx context.Context, houseID int, subscriber usecase
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM house WHERE id = $1)`
 
-	err := repo.db.Get(ctx, &exists, query, houseID)
+	err := repo.db.Get(postgres.WithPrimary(ctx), &exists, query, houseID)
 	if err != nil {
 		return err
 	}
@@ -42,3 +50,17 @@ func (repo *Repo) Subscribe(ctx context.Context, houseID int, subscriber usecase
 
 	return nil
 }
+
+// SubscriptionsByHouse returns the subscriptions of the given houses.
+func (repo *Repo) SubscriptionsByHouse(ctx context.Context, houseIDs []int) ([]usecase.Subscription, error) {
+	query := `
+		SELECT house_id, email
+		FROM subscriber
+		WHERE house_id = ANY($1)
+		ORDER BY house_id, id
+	`
+
+	subscriptions := make([]usecase.Subscription, 0)
+	err := repo.db.Select(ctx, &subscriptions, query, houseIDs)
+	return subscriptions, err
+}
@@ -3,34 +3,175 @@ package sender
 import (
 	"context"
 	"errors"
-	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/metrics"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"log/slog"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type Sender struct{}
+const (
+	success = "success"
+	failure = "failure"
 
-func New() *Sender {
-	return &Sender{}
+	tracerName = "github.com/NRKA/backend-bootcamp-assignment-2024/internal/service/sender"
+)
+
+var (
+	ErrNotRunning    = errors.New("sender is not running")
+	ErrDrainTimedOut = errors.New("sender did not finish pending notifications before the deadline")
+)
+
+// Queue is the persistent outbox of notifications waiting to be sent.
+type Queue interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]usecase.Notification, error)
+	MarkSent(ctx context.Context, id int) error
+	MarkFailed(ctx context.Context, id int, maxAttempts int, backoff time.Duration, cause error) error
+}
+
+type Config struct {
+	PollInterval time.Duration `yaml:"poll_interval" validate:"gt=0"`
+	BatchSize    int           `yaml:"batch_size" validate:"gt=0"`
+	Lease        time.Duration `yaml:"lease" validate:"gt=0"`
+	MaxAttempts  int           `yaml:"max_attempts" validate:"gt=0"`
+	RetryBackoff time.Duration `yaml:"retry_backoff" validate:"gte=0"`
+	DrainTimeout time.Duration `yaml:"drain_timeout" validate:"gte=0"`
+}
+
+type Sender struct {
+	queue   Queue
+	config  Config
+	running atomic.Bool
+	jobs    sync.WaitGroup
+}
+
+func New(queue Queue, config Config) *Sender {
+	return &Sender{queue: queue, config: config}
 }
 
-func (s *Sender) Run() error {
-	// Иммитация, что сервис Sender работает и в случаего чего вызывает метод SendEmail
+// Run dispatches due notifications until ctx is canceled. Notifications that
+// are already being sent are then drained for at most DrainTimeout; the ones
+// left unfinished are canceled and stay leased in the outbox, they are retried
+// once the lease expires. Run returns only after every job has stopped.
+func (s *Sender) Run(ctx context.Context) error {
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	// Jobs outlive ctx to finish the notifications they have claimed
+	jobs, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return s.drain(cancelJobs)
+		case <-ticker.C:
+			s.dispatch(jobs)
+		}
+	}
+}
+
+func (s *Sender) Check(ctx context.Context) error {
+	if !s.running.Load() {
+		return ErrNotRunning
+	}
 	return nil
 }
 
-func (s *Sender) SendEmail(ctx context.Context, recipient string, message string) error {
+func (s *Sender) dispatch(ctx context.Context) {
+	// Wait for the previous batch so that at most BatchSize emails are in flight
+	s.jobs.Wait()
+
+	notifications, err := s.queue.Claim(ctx, s.config.BatchSize, s.config.Lease)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to claim notifications", "error", err)
+		return
+	}
+
+	for _, notification := range notifications {
+		s.jobs.Add(1)
+		go func(notification usecase.Notification) {
+			defer s.jobs.Done()
+			s.deliver(ctx, notification)
+		}(notification)
+	}
+}
+
+func (s *Sender) deliver(ctx context.Context, notification usecase.Notification) {
+	ctx = logger.With(ctx, slog.Int("notification_id", notification.ID), slog.Int("attempt", notification.Attempts))
+	log := logger.FromContext(ctx)
+
+	if err := s.SendEmail(ctx, notification.Email, notification.Message); err != nil {
+		log.Warn("Failed to send notification", "error", err)
+		if err = s.queue.MarkFailed(ctx, notification.ID, s.config.MaxAttempts, s.config.RetryBackoff, err); err != nil {
+			log.Error("Failed to reschedule notification", "error", err)
+		}
+		return
+	}
+
+	if err := s.queue.MarkSent(ctx, notification.ID); err != nil {
+		log.Error("Failed to mark notification as sent", "error", err)
+	}
+}
+
+// drain waits for the jobs in flight, cancelJobs stops the ones still running
+// at the deadline so that none of them outlives the database pool.
+func (s *Sender) drain(cancelJobs context.CancelFunc) error {
+	done := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(s.config.DrainTimeout):
+		cancelJobs()
+		<-done
+		return ErrDrainTimedOut
+	}
+}
+
+func (s *Sender) SendEmail(ctx context.Context, recipient string, message string) (err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "sender.SendEmail")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	// Имитация отправки сообщения
 	duration := time.Duration(rand.Int63n(3000)) * time.Millisecond
-	time.Sleep(duration)
+	select {
+	case <-time.After(duration):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	metrics.NotificationDuration.Observe(duration.Seconds())
 
 	// Имитация неуспешной отправки сообщения
 	errorProbability := 0.1
 	if rand.Float64() < errorProbability {
+		metrics.NotificationsSent.WithLabelValues(failure).Inc()
 		return errors.New("internal error")
 	}
 
-	fmt.Printf("send message '%s' to '%s'\n", message, recipient)
+	metrics.NotificationsSent.WithLabelValues(success).Inc()
+
+	// The recipient and the message are personal data, the caller identifies the
+	// notification in the logger of ctx instead
+	logger.FromContext(ctx).Info("message sent", "duration", duration)
 
 	return nil
 }
@@ -0,0 +1,129 @@
+package sender
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeQueue struct {
+	mu       sync.Mutex
+	pending  []usecase.Notification
+	claimed  chan struct{}
+	finished map[int]bool
+	failed   map[int]error
+}
+
+func (q *fakeQueue) Claim(ctx context.Context, limit int, lease time.Duration) ([]usecase.Notification, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 {
+		return nil, nil
+	}
+	notifications := q.pending
+	q.pending = nil
+	close(q.claimed)
+	return notifications, nil
+}
+
+func (q *fakeQueue) MarkSent(ctx context.Context, id int) error {
+	q.finish(id)
+	return nil
+}
+
+func (q *fakeQueue) MarkFailed(ctx context.Context, id int, maxAttempts int, backoff time.Duration, cause error) error {
+	q.mu.Lock()
+	q.failed[id] = cause
+	q.mu.Unlock()
+
+	q.finish(id)
+	return ctx.Err()
+}
+
+func (q *fakeQueue) finish(id int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.finished[id] = true
+}
+
+func TestRunDrainsClaimedNotifications(t *testing.T) {
+	queue := &fakeQueue{
+		pending: []usecase.Notification{
+			{ID: 1, Email: "first@example.com", Message: "test", Attempts: 1},
+			{ID: 2, Email: "second@example.com", Message: "test", Attempts: 1},
+		},
+		claimed:  make(chan struct{}),
+		finished: make(map[int]bool),
+		failed:   make(map[int]error),
+	}
+	s := New(queue, Config{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    10,
+		Lease:        time.Minute,
+		MaxAttempts:  3,
+		DrainTimeout: 5 * time.Second,
+	})
+	require.ErrorIs(t, s.Check(context.Background()), ErrNotRunning)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	select {
+	case <-queue.claimed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notifications were not claimed")
+	}
+	require.NoError(t, s.Check(context.Background()))
+
+	// Отмена контекста не должна прерывать уже начатые отправки
+	cancel()
+	require.NoError(t, <-done)
+	require.Equal(t, map[int]bool{1: true, 2: true}, queue.finished)
+	require.ErrorIs(t, s.Check(context.Background()), ErrNotRunning)
+}
+
+func TestRunCancelsJobsAfterDrainTimeout(t *testing.T) {
+	queue := &fakeQueue{
+		pending: []usecase.Notification{
+			{ID: 1, Email: "first@example.com", Message: "test", Attempts: 1},
+			{ID: 2, Email: "second@example.com", Message: "test", Attempts: 1},
+		},
+		claimed:  make(chan struct{}),
+		finished: make(map[int]bool),
+		failed:   make(map[int]error),
+	}
+	s := New(queue, Config{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    10,
+		Lease:        time.Minute,
+		MaxAttempts:  3,
+		DrainTimeout: time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	select {
+	case <-queue.claimed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notifications were not claimed")
+	}
+
+	// Отправки, не успевшие завершиться к сроку, отменяются до возврата из Run
+	cancel()
+	require.ErrorIs(t, <-done, ErrDrainTimedOut)
+	require.Equal(t, map[int]bool{1: true, 2: true}, queue.finished)
+	for id, cause := range queue.failed {
+		require.ErrorIs(t, cause, context.Canceled, "notification %d", id)
+	}
+}
@@ -0,0 +1,22 @@
+package sender
+
+import (
+	"context"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/server/apierror"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/internal/usecase"
+)
+
+// SubscribeToHouse implements the subscription part of api.StrictServerInterface for /v2.
+func (h *Handler) SubscribeToHouse(ctx context.Context, request api.SubscribeToHouseRequestObject) (api.SubscribeToHouseResponseObject, error) {
+	email := usecase.Subscribe{Email: string(request.Body.Email)}
+	if err := email.Validate(); err != nil {
+		return nil, apierror.Validation(err)
+	}
+
+	if err := h.repo.Subscribe(ctx, request.Id, email); err != nil {
+		return nil, err
+	}
+
+	return api.SubscribeToHouse200Response{}, nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"github.com/NRKA/backend-bootcamp-assignment-2024/api"
+)
+
+// API converts the flat to its representation in api.yaml.
+func (f FlatResponse) API() api.Flat {
+	return api.Flat{
+		Id:      f.ID,
+		Number:  f.Number,
+		HouseId: f.HouseID,
+		Price:   f.Price,
+		Rooms:   f.Rooms,
+		Status:  api.Status(f.Status),
+	}
+}
+
+// APIFlats converts flats to their representation in api.yaml, an empty list
+// is kept as [] rather than null.
+func APIFlats(flats []FlatResponse) []api.Flat {
+	result := make([]api.Flat, 0, len(flats))
+	for _, f := range flats {
+		result = append(result, f.API())
+	}
+	return result
+}
+
+// API converts the house to its representation in api.yaml.
+func (h House) API() api.House {
+	house := api.House{
+		Id:        h.ID,
+		Address:   h.Address,
+		Year:      h.Year,
+		CreatedAt: &h.CreatedAt,
+		UpdatedAt: &h.UpdatedAt,
+	}
+	if h.Developer != "" {
+		house.Developer = &h.Developer
+	}
+	return house
+}
+
+// APIHouses converts houses to their representation in api.yaml.
+func APIHouses(houses []House) []api.House {
+	result := make([]api.House, 0, len(houses))
+	for _, h := range houses {
+		result = append(result, h.API())
+	}
+	return result
+}
+
+func NewFlatSearchRequest(params api.SearchFlatsParams) FlatSearchRequest {
+	return FlatSearchRequest{
+		PriceFrom: value(params.PriceFrom),
+		PriceTo:   value(params.PriceTo),
+		Rooms:     value(params.Rooms),
+		YearFrom:  value(params.YearFrom),
+		YearTo:    value(params.YearTo),
+		Developer: value(params.Developer),
+		Sort:      string(value(params.Sort)),
+		Limit:     value(params.Limit),
+		Offset:    value(params.Offset),
+	}
+}
+
+func NewHouseFlatsRequest(params api.ListHouseFlatsParams) HouseFlatsRequest {
+	limit := value(params.Limit)
+	if limit == 0 {
+		limit = DefaultHouseFlatsLimit
+	}
+
+	return HouseFlatsRequest{
+		Cursor:    value(params.Cursor),
+		Limit:     limit,
+		Sort:      string(value(params.Sort)),
+		Order:     string(value(params.Order)),
+		Status:    string(value(params.Status)),
+		Rooms:     value(params.Rooms),
+		PriceFrom: value(params.PriceFrom),
+		PriceTo:   value(params.PriceTo),
+	}
+}
+
+func NewHouseSearchRequest(params api.SearchHousesParams) HouseSearchRequest {
+	return HouseSearchRequest{Query: params.Q, Limit: value(params.Limit)}
+}
+
+// value returns the zero value for a parameter that was not sent, the same
+// as the usecase requests had for an empty query parameter.
+func value[T any](p *T) T {
+	var v T
+	if p != nil {
+		v = *p
+	}
+	return v
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import "time"
+
+// HouseExport is a house with the number of its flats.
+type HouseExport struct {
+	ID            int       `json:"id"`
+	Address       string    `json:"address"`
+	Year          int       `json:"year"`
+	Developer     string    `json:"developer"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Flats         int       `json:"flats"`
+	ApprovedFlats int       `json:"approved_flats"`
+}
+
+// FlatExport is a flat with the latest change of its status. ModeratedAt and
+// ModeratorID are nil if the status never changed or the moderator is unknown.
+type FlatExport struct {
+	ID          int        `json:"id"`
+	HouseID     int        `json:"house_id"`
+	Number      int        `json:"number"`
+	Price       int        `json:"price"`
+	Rooms       int        `json:"rooms"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Moderations int        `json:"moderations"`
+	ModeratedAt *time.Time `json:"moderated_at"`
+	ModeratorID *int       `json:"moderator_id"`
+}
+
+type FlatExportRequest struct {
+	Status string `validate:"omitempty,oneof=created on_moderate approved declined"`
+}
+
+func (r FlatExportRequest) Validate() error {
+	return validate.Struct(r)
+}
@@ -1,6 +1,6 @@
 package usecase
 
-import "github.com/go-playground/validator/v10"
+import "time"
 
 type FlatCreateRequest struct {
 	Number  int `json:"number" validate:"required,gt=0"`
@@ -21,14 +21,48 @@ type FlatResponse struct {
 type FlatUpdateRequest struct {
 	ID     int    `json:"id" validate:"required,gt=0"`
 	Status string `json:"status" validate:"required,oneof=on_moderate approved declined"`
+	// ModeratorID is recorded in the moderation history, 0 if unknown.
+	ModeratorID int `json:"-"`
+}
+
+// Moderation is a status change of a flat. ModeratorID is 0 if unknown.
+type Moderation struct {
+	ID          int       `json:"id"`
+	FlatID      int       `json:"flat_id"`
+	ModeratorID int       `json:"moderator_id"`
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type FlatSearchRequest struct {
+	PriceFrom    int    `validate:"gte=0"`
+	PriceTo      int    `validate:"omitempty,gtefield=PriceFrom"`
+	Rooms        int    `validate:"gte=0"`
+	YearFrom     int    `validate:"gte=0,lte=2100"`
+	YearTo       int    `validate:"omitempty,gtefield=YearFrom,lte=2100"`
+	Developer    string `validate:"max=255"`
+	Sort         string `validate:"omitempty,oneof=price_asc price_desc newest"`
+	Limit        int    `validate:"gte=0,lte=100"`
+	Offset       int    `validate:"gte=0"`
+	ApprovedOnly bool
+}
+
+type FlatSearchResponse struct {
+	Flats  []FlatResponse `json:"flats"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
 }
 
 func (r FlatCreateRequest) Validate() error {
-	validate := validator.New()
 	return validate.Struct(r)
 }
 
 func (r FlatUpdateRequest) Validate() error {
-	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r FlatSearchRequest) Validate() error {
 	return validate.Struct(r)
 }
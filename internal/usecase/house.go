@@ -1,9 +1,6 @@
 package usecase
 
-import (
-	"github.com/go-playground/validator/v10"
-	"time"
-)
+import "time"
 
 type HouseCreateRequest struct {
 	Address   string `json:"address" validate:"required"`
@@ -20,11 +17,45 @@ type House struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// DefaultHouseFlatsLimit is the page size of /v2 and gRPC flat lists when
+// the client sets no limit. v1 clients don't read the cursor, so they keep
+// getting every flat.
+const DefaultHouseFlatsLimit = 100
+
+// HouseFlatsRequest lists the flats of a house, Limit 0 returns all of them.
+type HouseFlatsRequest struct {
+	Cursor    string
+	Limit     int    `validate:"gte=0,lte=1000"`
+	Sort      string `validate:"omitempty,oneof=number price rooms"`
+	Order     string `validate:"omitempty,oneof=asc desc"`
+	Status    string `validate:"omitempty,oneof=created on_moderate approved declined"`
+	Rooms     int    `validate:"gte=0"`
+	PriceFrom int    `validate:"gte=0"`
+	PriceTo   int    `validate:"omitempty,gtefield=PriceFrom"`
+}
+
 type HouseFlats struct {
-	Flat []FlatResponse
+	Flat       []FlatResponse
+	NextCursor string `json:"-"`
+}
+
+type HouseSearchRequest struct {
+	Query string `validate:"required,max=255"`
+	Limit int    `validate:"gte=0,lte=100"`
+}
+
+type HouseSearchResponse struct {
+	Houses []House `json:"houses"`
 }
 
 func (r HouseCreateRequest) Validate() error {
-	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r HouseFlatsRequest) Validate() error {
+	return validate.Struct(r)
+}
+
+func (r HouseSearchRequest) Validate() error {
 	return validate.Struct(r)
 }
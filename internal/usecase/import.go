@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxImportRows limits the size of a single import.
+const MaxImportRows = 10000
+
+// ImportRow is a house or a flat of a bulk import. A flat references its house
+// either by HouseID or by HouseRef, the Ref of a house imported earlier in the
+// same file.
+type ImportRow struct {
+	Line      int    `json:"-"`
+	Kind      string `json:"kind"`
+	Ref       string `json:"ref"`
+	Address   string `json:"address"`
+	Year      int    `json:"year"`
+	Developer string `json:"developer"`
+	HouseID   int    `json:"house_id"`
+	HouseRef  string `json:"house_ref"`
+	Number    int    `json:"number"`
+	Price     int    `json:"price"`
+	Rooms     int    `json:"rooms"`
+	// Malformed tells why the line could not be parsed, the other fields are
+	// then incomplete.
+	Malformed string `json:"-"`
+}
+
+type ImportOptions struct {
+	DryRun bool
+	// ChunkSize is how many rows are committed together, 0 means all of them.
+	ChunkSize int `validate:"gte=0,lte=1000"`
+}
+
+type ImportReport struct {
+	Total    int
+	Imported int
+	Failed   int
+	DryRun   bool
+	// Aborted is set if an internal error stopped the import after some of its
+	// chunks were committed.
+	Aborted bool
+	Rows    []ImportRowResult
+}
+
+// ImportRowResult is the outcome of a row: ID is set if it was imported,
+// Err if it was not.
+type ImportRowResult struct {
+	Line int
+	Kind string
+	ID   int
+	Err  error
+}
+
+func (r ImportRow) House() HouseCreateRequest {
+	return HouseCreateRequest{Address: r.Address, Year: r.Year, Developer: r.Developer}
+}
+
+func (r ImportRow) Flat() FlatCreateRequest {
+	return FlatCreateRequest{Number: r.Number, HouseID: r.HouseID, Price: r.Price, Rooms: r.Rooms}
+}
+
+// Validate checks the row like the create request of its kind. The house of a
+// flat with HouseRef is only known during the import.
+func (r ImportRow) Validate() error {
+	if r.Malformed != "" {
+		return errors.New(r.Malformed)
+	}
+
+	switch r.Kind {
+	case "house":
+		if r.HouseID != 0 || r.HouseRef != "" || r.Number != 0 || r.Price != 0 || r.Rooms != 0 {
+			return errors.New("house rows must not have flat fields")
+		}
+		return r.House().Validate()
+	case "flat":
+		if r.Ref != "" || r.Address != "" || r.Year != 0 || r.Developer != "" {
+			return errors.New("flat rows must not have house fields")
+		}
+		if (r.HouseID == 0) == (r.HouseRef == "") {
+			return errors.New("exactly one of house_id and house_ref is required")
+		}
+		if r.HouseRef != "" {
+			return validate.StructExcept(r.Flat(), "HouseID")
+		}
+		return r.Flat().Validate()
+	default:
+		return fmt.Errorf("kind must be house or flat, got %q", r.Kind)
+	}
+}
+
+func (o ImportOptions) Validate() error {
+	return validate.Struct(o)
+}
@@ -1,7 +1,5 @@
 package usecase
 
-import "github.com/go-playground/validator/v10"
-
 type CreateUserRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
@@ -17,16 +15,29 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+type PasswordResetRequest struct {
+	ID       int    `validate:"required,gt=0"`
+	Password string `validate:"required,min=8"`
+}
+
 func (r CreateUserRequest) Validate() error {
-	validate := validator.New()
 	return validate.Struct(r)
 }
 
 func (r LoginRequest) Validate() error {
-	validate := validator.New()
 	return validate.Struct(r)
 }
 
+func (r PasswordResetRequest) Validate() error {
+	return validate.Struct(r)
+}
+
+type User struct {
+	ID       int    `json:"id"`
+	Email    string `json:"email"`
+	UserType string `json:"user_type"`
+}
+
 type LoginResponse struct {
 	Token string `json:"token"`
 }
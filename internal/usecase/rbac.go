@@ -0,0 +1,55 @@
+package usecase
+
+import "slices"
+
+// Permission is an action a role may perform, written as resource:action.
+type Permission string
+
+const (
+	PermHouseRead        Permission = "house:read"
+	PermHouseCreate      Permission = "house:create"
+	PermHouseSubscribe   Permission = "house:subscribe"
+	PermFlatCreate       Permission = "flat:create"
+	PermFlatModerate     Permission = "flat:moderate"
+	PermSubscriptionRead Permission = "subscription:read"
+	PermDataImport       Permission = "data:import"
+	PermDataExport       Permission = "data:export"
+	PermUserManage       Permission = "user:manage"
+)
+
+const (
+	RoleClient    = "client"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var (
+	clientPermissions    = []Permission{PermHouseRead, PermHouseSubscribe, PermFlatCreate}
+	moderatorPermissions = append(slices.Clip(clientPermissions),
+		PermHouseCreate, PermFlatModerate, PermSubscriptionRead, PermDataImport, PermDataExport)
+	adminPermissions = append(slices.Clip(moderatorPermissions), PermUserManage)
+)
+
+// DefaultRoles are the roles seeded by the rbac migration. The database is
+// the source of truth, DefaultRoles only backs the in-memory store and tokens
+// issued before permissions were added to the claims.
+var DefaultRoles = map[string][]Permission{
+	RoleClient:    clientPermissions,
+	RoleModerator: moderatorPermissions,
+	RoleAdmin:     adminPermissions,
+}
+
+// Role is a named set of permissions.
+type Role struct {
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+}
+
+type SetRoleRequest struct {
+	UserID int    `validate:"required,gt=0"`
+	Role   string `validate:"required,max=32"`
+}
+
+func (r SetRoleRequest) Validate() error {
+	return validate.Struct(r)
+}
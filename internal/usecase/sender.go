@@ -1,12 +1,21 @@
 package usecase
 
-import "github.com/go-playground/validator/v10"
-
 type Subscribe struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
 func (s Subscribe) Validate() error {
-	validate := validator.New()
 	return validate.Struct(s)
 }
+
+type Subscription struct {
+	HouseID int    `json:"house_id"`
+	Email   string `json:"email"`
+}
+
+type Notification struct {
+	ID       int    `json:"id"`
+	Email    string `json:"email"`
+	Message  string `json:"message"`
+	Attempts int    `json:"attempts"`
+}
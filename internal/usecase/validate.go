@@ -0,0 +1,7 @@
+package usecase
+
+import "github.com/go-playground/validator/v10"
+
+// validate is shared by all requests: the validator caches parsed struct tags
+// per instance and is safe for concurrent use.
+var validate = validator.New()
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+const cursorName = "stream_cursor"
+
+// Stream runs query through a server-side cursor and calls fn for every row,
+// fetching batchSize rows at a time, so results larger than memory can be
+// processed. The rows come from one snapshot of a read-only transaction on the
+// primary, held open until fn has seen the last row. Every batch, including the
+// calls of fn for its rows, is bounded by the query timeout. The transaction is never
+// retried, as fn may already have passed rows on. DECLARE takes no parameters,
+// so args are interpolated into the query by pgx on the client.
+func (db Database) Stream(ctx context.Context, batchSize int, query string, args []any, fn func(rows pgx.Rows) error) error {
+	opts := TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly, MaxRetries: -1}
+	return db.WithTx(ctx, opts, func(ctx context.Context, tx pgx.Tx) error {
+		declare := "DECLARE " + cursorName + " NO SCROLL CURSOR FOR " + query
+		_, err := tx.Exec(ctx, declare, append([]any{pgx.QueryExecModeSimpleProtocol}, args...)...)
+		if err != nil {
+			return err
+		}
+
+		fetch := fmt.Sprintf("FETCH %d FROM %s", batchSize, cursorName)
+		for {
+			fetched, err := fetchBatch(ctx, tx, fetch, fn)
+			if err != nil || fetched < batchSize {
+				return err
+			}
+		}
+	})
+}
+
+func fetchBatch(ctx context.Context, tx pgx.Tx, fetch string, fn func(rows pgx.Rows) error) (int, error) {
+	rows, err := tx.Query(ctx, fetch)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		fetched++
+		if err = fn(rows); err != nil {
+			return fetched, err
+		}
+	}
+	return fetched, rows.Err()
+}
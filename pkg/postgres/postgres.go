@@ -7,62 +7,198 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+	"sync/atomic"
+	"time"
 )
 
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
+	Host     string `yaml:"host" validate:"required"`
+	Port     string `yaml:"port" validate:"required,numeric"`
+	User     string `yaml:"user" validate:"required"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name" validate:"required"`
+	// SSLMode is passed to libpq as sslmode, disable when empty.
+	SSLMode string `yaml:"ssl_mode" validate:"omitempty,oneof=disable allow prefer require verify-ca verify-full"`
+
+	// Zero values of the pool settings keep the pgxpool defaults.
+	MaxConns          int32         `yaml:"max_conns" validate:"gte=0"`
+	MinConns          int32         `yaml:"min_conns" validate:"gte=0"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" validate:"gte=0"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" validate:"gte=0"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" validate:"gte=0"`
+
+	// StatementTimeout is enforced by the server for every statement of the pool.
+	StatementTimeout time.Duration `yaml:"statement_timeout" validate:"gte=0"`
+	// QueryTimeout bounds every Database call and statement in a transaction on
+	// the client side, unless the caller's context already expires earlier.
+	QueryTimeout time.Duration `yaml:"query_timeout" validate:"gte=0"`
+
+	// Replicas are "host[:port]" addresses of read replicas sharing the primary's
+	// credentials and pool settings. Get and Select outside transactions go to
+	// them unless the context is marked WithPrimary.
+	Replicas []string `yaml:"replicas" validate:"dive,required"`
 }
 
 type Database struct {
-	cluster *pgxpool.Pool
+	cluster      *pgxpool.Pool
+	replicas     []*replica
+	next         *atomic.Uint64
+	stop         context.CancelFunc
+	queryTimeout time.Duration
 }
 
 func NewDB(ctx context.Context, dbConfig DatabaseConfig) (*Database, error) {
-	pool, err := pgxpool.New(ctx, GenerateDsn(dbConfig))
+	pool, err := newPool(ctx, dbConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Database{cluster: pool}, nil
+	db := &Database{
+		cluster:      pool,
+		next:         new(atomic.Uint64),
+		stop:         func() {},
+		queryTimeout: dbConfig.QueryTimeout,
+	}
+
+	for _, address := range dbConfig.Replicas {
+		replicaConfig := dbConfig
+		replicaConfig.Host, replicaConfig.Port = replicaHost(address, dbConfig.Port)
+
+		replicaPool, err := newPool(ctx, replicaConfig)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("replica %s: %w", address, err)
+		}
+
+		r := &replica{name: address, pool: replicaPool}
+		r.healthy.Store(true)
+		db.replicas = append(db.replicas, r)
+	}
+
+	if len(db.replicas) > 0 {
+		monitorCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+		db.stop = stop
+		go db.monitor(monitorCtx, defaultReplicaCheckPeriod)
+	}
+
+	return db, nil
+}
+
+func newPool(ctx context.Context, dbConfig DatabaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(GenerateDsn(dbConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	if dbConfig.MaxConns > 0 {
+		poolConfig.MaxConns = dbConfig.MaxConns
+	}
+	if dbConfig.MinConns > 0 {
+		poolConfig.MinConns = dbConfig.MinConns
+	}
+	if dbConfig.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = dbConfig.MaxConnLifetime
+	}
+	if dbConfig.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = dbConfig.MaxConnIdleTime
+	}
+	if dbConfig.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = dbConfig.HealthCheckPeriod
+	}
+	if dbConfig.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(dbConfig.StatementTimeout.Milliseconds(), 10)
+	}
+
+	return pgxpool.NewWithConfig(ctx, poolConfig)
 }
 
 func GenerateDsn(dbConfig DatabaseConfig) string {
-	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.Name)
+	sslMode := dbConfig.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.Name, sslMode)
 }
 
 func (db Database) GetPool() *pgxpool.Pool {
 	return db.cluster
 }
 
+// ReplicaPools returns the replica pools by address.
+func (db Database) ReplicaPools() map[string]*pgxpool.Pool {
+	pools := make(map[string]*pgxpool.Pool, len(db.replicas))
+	for _, r := range db.replicas {
+		pools[r.name] = r.pool
+	}
+	return pools
+}
+
+// Close stops the replica health checks and closes the replica pools, then the primary.
+func (db Database) Close() {
+	db.stop()
+	for _, r := range db.replicas {
+		r.pool.Close()
+	}
+	db.cluster.Close()
+}
+
 func (db Database) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return pgxscan.Get(ctx, db.cluster, dest, query, args...)
+	if tx, ok := txFromContext(ctx); ok {
+		return pgxscan.Get(ctx, tx, dest, query, args...)
+	}
+
+	ctx, cancel := withTimeout(ctx, db.queryTimeout)
+	defer cancel()
+
+	return db.read(ctx, "postgres.Get", query, func(ctx context.Context, querier pgxscan.Querier) error {
+		return pgxscan.Get(ctx, querier, dest, query, args...)
+	})
 }
 
 func (db Database) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return pgxscan.Select(ctx, db.cluster, dest, query, args...)
+	if tx, ok := txFromContext(ctx); ok {
+		return pgxscan.Select(ctx, tx, dest, query, args...)
+	}
+
+	ctx, cancel := withTimeout(ctx, db.queryTimeout)
+	defer cancel()
+
+	return db.read(ctx, "postgres.Select", query, func(ctx context.Context, querier pgxscan.Querier) error {
+		return pgxscan.Select(ctx, querier, dest, query, args...)
+	})
 }
 
 func (db Database) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
-	return db.cluster.Exec(ctx, query, args...)
-}
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.Exec(ctx, query, args...)
+	}
 
-func (db Database) ExecQueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
-	return db.cluster.QueryRow(ctx, query, args...)
-}
+	ctx, cancel := withTimeout(ctx, db.queryTimeout)
+	defer cancel()
 
-func (db Database) BeginTx(ctx context.Context) (pgx.Tx, error) {
-	return db.cluster.Begin(ctx)
+	ctx, span := startSpan(ctx, "postgres.Exec", query)
+	tag, err := db.cluster.Exec(ctx, query, args...)
+	endSpan(span, err)
+	return tag, err
 }
 
-func (db Database) CommitTx(ctx context.Context, tx pgx.Tx) error {
-	return tx.Commit(ctx)
+func (db Database) ExecQueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.QueryRow(ctx, query, args...)
+	}
+
+	ctx, cancel := withTimeout(ctx, db.queryTimeout)
+	ctx, span := startSpan(ctx, "postgres.QueryRow", query)
+	return tracedRow{row: db.cluster.QueryRow(ctx, query, args...), span: span, cancel: cancel}
 }
 
-func (db Database) RollbackTx(ctx context.Context, tx pgx.Tx) error {
-	return tx.Rollback(ctx)
+// withTimeout bounds a single operation by timeout. A zero timeout leaves ctx as is.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
 }
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const defaultReplicaCheckPeriod = 10 * time.Second
+
+type primaryKey struct{}
+
+// WithPrimary makes Get and Select with the returned context go to the primary.
+// Use it for statements that write (INSERT ... RETURNING) and for reads that
+// must observe a write that may not have reached the replicas yet.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+type replica struct {
+	name    string
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+func (r *replica) setHealthy(ctx context.Context, healthy bool, cause error) {
+	if r.healthy.Swap(healthy) == healthy {
+		return
+	}
+
+	if healthy {
+		logger.FromContext(ctx).Info("Replica is back in rotation", "replica", r.name)
+	} else {
+		logger.FromContext(ctx).Warn("Replica taken out of rotation", "replica", r.name, "error", cause)
+	}
+}
+
+// replicaHost splits "host[:port]" falling back to the primary port.
+func replicaHost(address, defaultPort string) (string, string) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address, defaultPort
+	}
+	return host, port
+}
+
+// replica picks the next healthy replica round-robin. It returns nil when
+// reads must go to the primary.
+func (db Database) replica(ctx context.Context) *replica {
+	if len(db.replicas) == 0 || usePrimary(ctx) {
+		return nil
+	}
+
+	healthy := make([]*replica, 0, len(db.replicas))
+	for _, r := range db.replicas {
+		if r.healthy.Load() {
+			healthy = append(healthy, r)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	return healthy[db.next.Add(1)%uint64(len(healthy))]
+}
+
+// read runs a query on a replica and falls back to the primary if the replica
+// is unreachable, taking it out of rotation until the next health check.
+func (db Database) read(ctx context.Context, name, query string, run func(context.Context, pgxscan.Querier) error) error {
+	if r := db.replica(ctx); r != nil {
+		replicaCtx, span := startSpan(ctx, name, query)
+		span.SetAttributes(attribute.String("db.replica", r.name))
+		err := run(replicaCtx, r.pool)
+		endSpan(span, err)
+		if !unavailable(err) {
+			return err
+		}
+		r.setHealthy(ctx, false, err)
+	}
+
+	ctx, span := startSpan(ctx, name, query)
+	err := run(ctx, db.cluster)
+	endSpan(span, err)
+	return err
+}
+
+// monitor pings every replica each period and updates its health until ctx is done.
+func (db Database) monitor(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, r := range db.replicas {
+				pingCtx, cancel := context.WithTimeout(ctx, period)
+				err := r.pool.Ping(pingCtx)
+				cancel()
+				r.setHealthy(ctx, err == nil, err)
+			}
+		}
+	}
+}
+
+// unavailable reports whether err means the server could not run the query at
+// all, as opposed to the query itself failing.
+func unavailable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) || errors.As(err, &netErr) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 08 is connection exceptions, 57P01-57P03 are shutdowns and startup
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+
+	return false
+}
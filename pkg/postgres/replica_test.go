@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+)
+
+func testDatabase(names ...string) Database {
+	db := Database{next: new(atomic.Uint64)}
+	for _, name := range names {
+		r := &replica{name: name}
+		r.healthy.Store(true)
+		db.replicas = append(db.replicas, r)
+	}
+	return db
+}
+
+func TestReplicaRoundRobinSkipsUnhealthy(t *testing.T) {
+	db := testDatabase("a", "b", "c")
+	db.replicas[1].healthy.Store(false)
+
+	seen := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		seen[db.replica(context.Background()).name]++
+	}
+	require.Equal(t, map[string]int{"a": 2, "c": 2}, seen)
+}
+
+func TestReplicaFallsBackToPrimary(t *testing.T) {
+	require.Nil(t, testDatabase().replica(context.Background()))
+
+	db := testDatabase("a")
+	require.Nil(t, db.replica(WithPrimary(context.Background())))
+
+	db.replicas[0].healthy.Store(false)
+	require.Nil(t, db.replica(context.Background()))
+}
+
+func TestUnavailable(t *testing.T) {
+	require.False(t, unavailable(nil))
+	require.False(t, unavailable(pgx.ErrNoRows))
+	require.False(t, unavailable(context.DeadlineExceeded))
+	require.False(t, unavailable(&pgconn.PgError{Code: "42P01"}))
+	require.True(t, unavailable(fmt.Errorf("query: %w", &pgconn.PgError{Code: "57P01"})))
+	require.True(t, unavailable(&pgconn.PgError{Code: "08006"}))
+	require.False(t, unavailable(errors.New("scany: column not found")))
+}
+
+func TestReplicaHost(t *testing.T) {
+	host, port := replicaHost("replica-1:5433", "5432")
+	require.Equal(t, "replica-1", host)
+	require.Equal(t, "5433", port)
+
+	host, port = replicaHost("replica-2", "5432")
+	require.Equal(t, "replica-2", host)
+	require.Equal(t, "5432", port)
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+const tracerName = "github.com/NRKA/backend-bootcamp-assignment-2024/pkg/postgres"
+
+func startSpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{semconv.DBSystemPostgreSQL}
+	if query != "" {
+		attrs = append(attrs, semconv.DBQueryText(query))
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type tracedRow struct {
+	row    pgx.Row
+	span   trace.Span
+	cancel context.CancelFunc
+}
+
+func (r tracedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	endSpan(r.span, err)
+	r.cancel()
+	return err
+}
+
+// timedRows releases the operation deadline once the rows are closed.
+type timedRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r timedRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// tracedTx keeps a span open for the whole transaction and parents the spans
+// of the statements executed within it.
+type tracedTx struct {
+	pgx.Tx
+	span    trace.Span
+	timeout time.Duration
+}
+
+func (tx *tracedTx) childContext(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(ctx, tx.span)
+}
+
+func (tx *tracedTx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := withTimeout(ctx, tx.timeout)
+	defer cancel()
+
+	ctx, span := startSpan(tx.childContext(ctx), "postgres.Tx.Exec", sql)
+	tag, err := tx.Tx.Exec(ctx, sql, arguments...)
+	endSpan(span, err)
+	return tag, err
+}
+
+func (tx *tracedTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := withTimeout(ctx, tx.timeout)
+	ctx, span := startSpan(tx.childContext(ctx), "postgres.Tx.Query", sql)
+	rows, err := tx.Tx.Query(ctx, sql, args...)
+	endSpan(span, err)
+	if err != nil {
+		cancel()
+		return rows, err
+	}
+	return timedRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (tx *tracedTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := withTimeout(ctx, tx.timeout)
+	ctx, span := startSpan(tx.childContext(ctx), "postgres.Tx.QueryRow", sql)
+	return tracedRow{row: tx.Tx.QueryRow(ctx, sql, args...), span: span, cancel: cancel}
+}
+
+func (tx *tracedTx) Commit(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, tx.timeout)
+	defer cancel()
+
+	err := tx.Tx.Commit(tx.childContext(ctx))
+	tx.end(err)
+	return err
+}
+
+func (tx *tracedTx) Rollback(ctx context.Context) error {
+	err := tx.Tx.Rollback(tx.childContext(ctx))
+	if errors.Is(err, pgx.ErrTxClosed) {
+		return err
+	}
+
+	tx.span.SetAttributes(attribute.Bool("db.transaction.rolled_back", true))
+	tx.end(err)
+	return err
+}
+
+func (tx *tracedTx) end(err error) {
+	if tx.span.IsRecording() {
+		endSpan(tx.span, err)
+	}
+}
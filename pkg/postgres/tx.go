@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/NRKA/backend-bootcamp-assignment-2024/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultTxRetries = 3
+	txRetryBackoff   = 20 * time.Millisecond
+
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+type TxOptions struct {
+	IsoLevel   pgx.TxIsoLevel
+	AccessMode pgx.TxAccessMode
+	// MaxRetries is how many times the transaction is rerun after a serialization
+	// failure or a deadlock. Zero means defaultTxRetries, negative disables retries.
+	MaxRetries int
+}
+
+type txKey struct{}
+
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// WithTx runs fn in a transaction and commits it if fn returns nil. The context
+// passed to fn carries the transaction: Get, Select, Exec, ExecQueryRow and
+// nested WithTx calls made with it join the transaction, so repositories can be
+// composed. A nested WithTx ignores opts and leaves retries to the outermost one,
+// therefore fn must be safe to rerun.
+func (db Database) WithTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return fn(ctx, tx)
+	}
+
+	retries := opts.MaxRetries
+	if retries == 0 {
+		retries = defaultTxRetries
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := db.runTx(ctx, opts, fn)
+		if err == nil || attempt > retries || !retryable(err) {
+			return err
+		}
+
+		logger.FromContext(ctx).Debug("Retrying transaction", "attempt", attempt, "error", err)
+
+		backoff := time.Duration(attempt)*txRetryBackoff + time.Duration(rand.Int63n(int64(txRetryBackoff)))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		}
+	}
+}
+
+// WithSavepoint runs fn in a savepoint of the context transaction: if fn fails,
+// only its own changes are rolled back and the transaction can go on. Without a
+// context transaction it is WithTx with the default options.
+func (db Database) WithSavepoint(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	outer, ok := txFromContext(ctx)
+	if !ok {
+		return db.WithTx(ctx, TxOptions{}, fn)
+	}
+
+	spanCtx := ctx
+	if traced, ok := outer.(*tracedTx); ok {
+		spanCtx = traced.childContext(ctx)
+	}
+	_, span := startSpan(spanCtx, "postgres.Savepoint", "")
+
+	savepoint, err := outer.Begin(ctx)
+	if err != nil {
+		endSpan(span, err)
+		return err
+	}
+
+	return finishTx(ctx, &tracedTx{Tx: savepoint, span: span, timeout: db.queryTimeout}, fn)
+}
+
+func (db Database) runTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	tx, err := db.beginTx(ctx, pgx.TxOptions{IsoLevel: opts.IsoLevel, AccessMode: opts.AccessMode})
+	if err != nil {
+		return err
+	}
+
+	return finishTx(ctx, tx, fn)
+}
+
+// finishTx runs fn with tx in the context, then commits tx or rolls it back.
+func finishTx(ctx context.Context, tx pgx.Tx, fn func(ctx context.Context, tx pgx.Tx) error) (err error) {
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		// The request context may already be canceled, the rollback must still reach the server
+		rollbackErr := tx.Rollback(context.WithoutCancel(ctx))
+		if rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			err = errors.Join(err, fmt.Errorf("rollback: %w", rollbackErr))
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx), tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+func (db Database) beginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	ctx, span := startSpan(ctx, "postgres.Transaction", "")
+	beginCtx, cancel := withTimeout(ctx, db.queryTimeout)
+	defer cancel()
+
+	tx, err := db.cluster.BeginTx(beginCtx, opts)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+
+	return &tracedTx{Tx: tx, span: span, timeout: db.queryTimeout}, nil
+}
+
+func retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected)
+}
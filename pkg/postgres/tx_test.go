@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type stubTx struct {
+	pgx.Tx
+	savepoints            []*stubTx
+	committed, rolledBack bool
+}
+
+func (tx *stubTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	savepoint := &stubTx{}
+	tx.savepoints = append(tx.savepoints, savepoint)
+	return savepoint, nil
+}
+
+func (tx *stubTx) Commit(ctx context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *stubTx) Rollback(ctx context.Context) error {
+	if tx.committed {
+		return pgx.ErrTxClosed
+	}
+	tx.rolledBack = true
+	return nil
+}
+
+func TestWithTxJoinsContextTransaction(t *testing.T) {
+	outer := &stubTx{}
+	ctx := context.WithValue(context.Background(), txKey{}, pgx.Tx(outer))
+
+	calls := 0
+	err := Database{}.WithTx(ctx, TxOptions{}, func(ctx context.Context, tx pgx.Tx) error {
+		calls++
+		require.Same(t, outer, tx)
+		return &pgconn.PgError{Code: serializationFailure}
+	})
+
+	// The nested call is not retried, the outermost WithTx reruns the whole transaction
+	require.Equal(t, 1, calls)
+	require.True(t, retryable(err))
+}
+
+func TestWithSavepoint(t *testing.T) {
+	outer := &stubTx{}
+	ctx := context.WithValue(context.Background(), txKey{}, pgx.Tx(outer))
+	failed := errors.New("row failed")
+
+	err := Database{}.WithSavepoint(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		inner, ok := txFromContext(ctx)
+		require.True(t, ok)
+		require.Same(t, tx, inner)
+		require.NotSame(t, outer, inner)
+		return failed
+	})
+	require.Equal(t, failed, err)
+
+	err = Database{}.WithSavepoint(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Only the savepoints end, the outer transaction goes on
+	require.Len(t, outer.savepoints, 2)
+	require.True(t, outer.savepoints[0].rolledBack)
+	require.True(t, outer.savepoints[1].committed)
+	require.False(t, outer.committed || outer.rolledBack)
+}
+
+func TestRetryable(t *testing.T) {
+	require.True(t, retryable(&pgconn.PgError{Code: serializationFailure}))
+	require.True(t, retryable(fmt.Errorf("update: %w", &pgconn.PgError{Code: deadlockDetected})))
+	require.False(t, retryable(&pgconn.PgError{Code: "23505"}))
+	require.False(t, retryable(errors.New("boom")))
+	require.False(t, retryable(nil))
+}
@@ -0,0 +1,53 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+)
+
+type sheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "flats")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow([]any{"id", "address", "developer"}))
+	require.NoError(t, w.WriteRow([]any{1, `Лесная <улица> & "7"`, nil}))
+	require.NoError(t, w.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	parts := make(map[string][]byte)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		parts[f.Name], err = io.ReadAll(r)
+		require.NoError(t, err)
+	}
+	require.Contains(t, parts, "[Content_Types].xml")
+	require.Contains(t, string(parts["xl/workbook.xml"]), `name="flats"`)
+
+	var s sheet
+	require.NoError(t, xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &s))
+	require.Len(t, s.Rows, 2)
+	require.Equal(t, 2, s.Rows[1].R)
+	require.Equal(t, "inlineStr", s.Rows[0].Cells[0].Type)
+	require.Equal(t, "id", s.Rows[0].Cells[0].Inline)
+	require.Equal(t, "1", s.Rows[1].Cells[0].Value)
+	require.Equal(t, `Лесная <улица> & "7"`, s.Rows[1].Cells[1].Inline)
+	require.Len(t, s.Rows[1].Cells, 3)
+}